@@ -0,0 +1,207 @@
+// Package analysis scores channel congestion from scan results and
+// recommends where to place a new access point.
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"wifiscanner/scanner"
+	"wifiscanner/scanner/channel"
+)
+
+// noiseFloor is the interference level reported for an empty channel, dBm.
+const noiseFloor = -100
+
+// candidates are the channels a new AP may use in each band, primary 20 MHz
+// channel numbers. 2.4 GHz channel 14 (Japan only) is left out.
+var candidates = map[channel.Band][]int{
+	channel.Band2G: channel.Range(1, 13, 1),
+	channel.Band5G: {36, 40, 44, 48, 52, 56, 60, 64, 100, 104, 108, 112, 116, 120, 124, 128, 132, 136, 140, 144, 149, 153, 157, 161, 165},
+	channel.Band6G: channel.Range(1, 233, 4),
+}
+
+// widths are the placements recommended per band. Bonding on 2.4 GHz
+// leaves no room for neighbours, so it is 20 MHz only.
+var widths = map[channel.Band][]int{
+	channel.Band2G: {20},
+	channel.Band5G: {20, 40, 80},
+	channel.Band6G: {20, 40, 80},
+}
+
+var bandOrder = []channel.Band{channel.Band2G, channel.Band5G, channel.Band6G}
+
+// ChannelScore is the congestion of one 20 MHz channel.
+type ChannelScore struct {
+	Band      string  `json:"band"`
+	Channel   int     `json:"channel"`
+	Frequency int     `json:"frequency"`
+	Score     float64 `json:"score"`      // aggregate interference, dBm; lower is better
+	CoChannel int     `json:"co_channel"` // networks with this primary channel
+	Adjacent  int     `json:"adjacent"`   // other networks overlapping the channel
+	Strongest int     `json:"strongest"`  // strongest overlapping signal, dBm, 0 if none
+
+	power float64 // linear interference, mW
+}
+
+// Placement is a recommended channel block for a new AP.
+type Placement struct {
+	Band     string  `json:"band"`
+	Width    int     `json:"width"`
+	Primary  int     `json:"primary"`
+	Channels []int   `json:"channels"`
+	Center   int     `json:"center_frequency"`
+	Score    float64 `json:"score"` // interference across the block, dBm
+}
+
+// Report is the result of one analysis run.
+type Report struct {
+	Time            time.Time      `json:"time"`
+	Networks        int            `json:"networks"`
+	Channels        []ChannelScore `json:"channels"`
+	Recommendations []Placement    `json:"recommendations"`
+}
+
+// Analyze scores every candidate channel in the bands the scan saw and picks
+// the best placement per band and width. Each network contributes its
+// signal power, scaled by how much of the channel its span overlaps and, if
+// session is non-nil, by how persistently it has been seen. If reg is
+// non-nil, channels and widths it does not permit are left out.
+func Analyze(networks []scanner.Network, session *scanner.Session, reg *scanner.RegDomain) Report {
+	report := Report{Time: time.Now(), Networks: len(networks)}
+
+	seen := make(map[channel.Band]bool)
+	for _, n := range networks {
+		seen[channel.BandOf(n.Frequency)] = true
+	}
+
+	for _, band := range bandOrder {
+		if !seen[band] {
+			continue
+		}
+		scores := make(map[int]ChannelScore)
+		for _, ch := range candidates[band] {
+			if !permitted(reg, band, ch, 20) {
+				continue
+			}
+			cs := scoreChannel(band, ch, networks, session)
+			scores[ch] = cs
+			report.Channels = append(report.Channels, cs)
+		}
+		for _, width := range widths[band] {
+			if p, ok := bestPlacement(band, width, scores, reg); ok {
+				report.Recommendations = append(report.Recommendations, p)
+			}
+		}
+	}
+	return report
+}
+
+// scoreChannel sums the interference every network puts on one 20 MHz
+// channel.
+func scoreChannel(band channel.Band, ch int, networks []scanner.Network, session *scanner.Session) ChannelScore {
+	freq := channel.Frequency(band, ch)
+	target := channel.New(freq, 20, 0, 0, 0)
+	cs := ChannelScore{Band: band.String(), Channel: ch, Frequency: freq}
+
+	for _, n := range networks {
+		overlap := n.Span().Overlap(target)
+		if overlap == 0 {
+			continue
+		}
+		if n.Frequency == freq {
+			cs.CoChannel++
+		} else {
+			cs.Adjacent++
+		}
+		if cs.Strongest == 0 || n.Signal > cs.Strongest {
+			cs.Strongest = n.Signal
+		}
+
+		weight := float64(overlap) / 20
+		if session != nil {
+			if p := session.Persistence(n.BSSID); p > 0 {
+				weight *= p
+			}
+		}
+		cs.power += dbmToMW(n.Signal) * weight
+	}
+	cs.Score = mwToDBm(cs.power + dbmToMW(noiseFloor))
+	return cs
+}
+
+// bestPlacement finds the block of the given width with the least total
+// interference, using its cleanest 20 MHz channel as primary. Blocks that
+// include a channel outside the candidate list, or wider than reg allows
+// there, are skipped.
+func bestPlacement(band channel.Band, width int, scores map[int]ChannelScore, reg *scanner.RegDomain) (Placement, bool) {
+	var best Placement
+	found := false
+	tried := make(map[int]bool)
+
+	chans := make([]int, 0, len(scores))
+	for ch := range scores {
+		chans = append(chans, ch)
+	}
+	sort.Ints(chans)
+
+	for _, ch := range chans {
+		span := channel.New(channel.Frequency(band, ch), width, 0, 0, 0)
+		if tried[span.Center] {
+			continue
+		}
+		tried[span.Center] = true
+
+		block := span.Channels()
+		power, primary, legal := 0.0, 0, true
+		for _, sub := range block {
+			cs, ok := scores[sub]
+			if !ok || !permitted(reg, band, sub, width) {
+				legal = false
+				break
+			}
+			power += cs.power
+			if primary == 0 || cs.power < scores[primary].power {
+				primary = sub
+			}
+		}
+		if !legal || len(block) == 0 {
+			continue
+		}
+
+		score := mwToDBm(power + dbmToMW(noiseFloor))
+		if !found || score < best.Score {
+			best = Placement{
+				Band:     band.String(),
+				Width:    width,
+				Primary:  primary,
+				Channels: block,
+				Center:   span.Center,
+				Score:    score,
+			}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// permitted reports whether reg lets a block of the given width use
+// channel ch. A nil domain, or the world domain ("00"), which is only the
+// kernel's conservative fallback, permits everything.
+func permitted(reg *scanner.RegDomain, band channel.Band, ch, width int) bool {
+	if reg == nil || reg.Country == "00" {
+		return true
+	}
+	rule, ok := reg.Rule(channel.Frequency(band, ch))
+	// AUTO-BW rules may be bonded with their neighbours past MaxBandwidth
+	return ok && (rule.MaxBandwidth >= width || rule.Has("AUTO-BW"))
+}
+
+func dbmToMW(dbm int) float64 {
+	return math.Pow(10, float64(dbm)/10)
+}
+
+func mwToDBm(mw float64) float64 {
+	return math.Round(10*math.Log10(mw)*10) / 10
+}
@@ -0,0 +1,135 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"wifiscanner/scanner"
+	"wifiscanner/scanner/channel"
+)
+
+func TestScoreChannel(t *testing.T) {
+	networks := []scanner.Network{
+		{BSSID: "AA:00:00:00:00:01", Frequency: 2437, Signal: -50, PHY: scanner.PHYInfo{ChannelWidth: 20}},
+		{BSSID: "AA:00:00:00:00:02", Frequency: 5180, Signal: -60, PHY: scanner.PHYInfo{ChannelWidth: 40, SecondaryOffset: 1}},
+	}
+	tests := []struct {
+		name string
+		band channel.Band
+		ch   int
+		want ChannelScore
+	}{
+		{"co-channel", channel.Band2G, 6, ChannelScore{Band: "2.4 GHz", Channel: 6, Frequency: 2437, Score: -50, CoChannel: 1, Strongest: -50}},
+		{"three quarters overlap", channel.Band2G, 7, ChannelScore{Band: "2.4 GHz", Channel: 7, Frequency: 2442, Score: -51.2, Adjacent: 1, Strongest: -50}},
+		{"half overlap", channel.Band2G, 8, ChannelScore{Band: "2.4 GHz", Channel: 8, Frequency: 2447, Score: -53, Adjacent: 1, Strongest: -50}},
+		{"clear", channel.Band2G, 11, ChannelScore{Band: "2.4 GHz", Channel: 11, Frequency: 2462, Score: noiseFloor}},
+		{"secondary of 40 MHz", channel.Band5G, 40, ChannelScore{Band: "5 GHz", Channel: 40, Frequency: 5200, Score: -60, Adjacent: 1, Strongest: -60}},
+		{"beside 40 MHz", channel.Band5G, 44, ChannelScore{Band: "5 GHz", Channel: 44, Frequency: 5220, Score: noiseFloor}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scoreChannel(tt.band, tt.ch, networks, nil)
+			got.power = 0
+			if got != tt.want {
+				t.Errorf("scoreChannel() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreChannelPersistence(t *testing.T) {
+	n := scanner.Network{BSSID: "AA:00:00:00:00:01", Frequency: 2437, Signal: -50, PHY: scanner.PHYInfo{ChannelWidth: 20}}
+	session := scanner.NewSession()
+	session.Update([]scanner.Network{n})
+	session.Update(nil)
+
+	// Seen in one scan of two: half the power
+	if got := scoreChannel(channel.Band2G, 6, []scanner.Network{n}, session).Score; got != -53 {
+		t.Errorf("score = %.1f, want -53.0", got)
+	}
+}
+
+// fiveGHz has an 80 MHz network on 36-48 and a 20 MHz one on 149.
+var fiveGHz = []scanner.Network{
+	{BSSID: "AA:00:00:00:00:01", Frequency: 5180, Signal: -50, PHY: scanner.PHYInfo{ChannelWidth: 80, CenterSeg0: 42}},
+	{BSSID: "AA:00:00:00:00:02", Frequency: 5745, Signal: -70, PHY: scanner.PHYInfo{ChannelWidth: 20}},
+}
+
+func scores(band channel.Band, chans []int, networks []scanner.Network) map[int]ChannelScore {
+	m := make(map[int]ChannelScore)
+	for _, ch := range chans {
+		m[ch] = scoreChannel(band, ch, networks, nil)
+	}
+	return m
+}
+
+func TestBestPlacement(t *testing.T) {
+	all := scores(channel.Band5G, candidates[channel.Band5G], fiveGHz)
+	tests := []struct {
+		name   string
+		width  int
+		scores map[int]ChannelScore
+		want   Placement
+		ok     bool
+	}{
+		{"20 MHz", 20, all, Placement{Band: "5 GHz", Width: 20, Primary: 52, Channels: []int{52}, Center: 5260, Score: noiseFloor}, true},
+		{"40 MHz", 40, all, Placement{Band: "5 GHz", Width: 40, Primary: 52, Channels: []int{52, 56}, Center: 5270, Score: noiseFloor}, true},
+		{"80 MHz", 80, all, Placement{Band: "5 GHz", Width: 80, Primary: 52, Channels: []int{52, 56, 60, 64}, Center: 5290, Score: noiseFloor}, true},
+		{
+			"cleanest primary", 40, scores(channel.Band5G, []int{149, 153}, fiveGHz),
+			Placement{Band: "5 GHz", Width: 40, Primary: 153, Channels: []int{149, 153}, Center: 5755, Score: -70}, true,
+		},
+		{"incomplete block", 80, scores(channel.Band5G, []int{36, 40, 44}, nil), Placement{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := bestPlacement(channel.Band5G, tt.width, tt.scores, nil)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bestPlacement() = %+v, %v; want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAnalyzeRegulatory(t *testing.T) {
+	reg := &scanner.RegDomain{
+		Country: "XX",
+		Rules: []scanner.RegRule{
+			{StartFreq: 5150, EndFreq: 5250, MaxBandwidth: 80},
+			{StartFreq: 5735, EndFreq: 5835, MaxBandwidth: 40},
+		},
+	}
+	report := Analyze(fiveGHz, nil, reg)
+
+	var chans []int
+	for _, cs := range report.Channels {
+		chans = append(chans, cs.Channel)
+	}
+	if want := []int{36, 40, 44, 48, 149, 153, 157, 161, 165}; !reflect.DeepEqual(chans, want) {
+		t.Errorf("channels = %v, want %v", chans, want)
+	}
+
+	want := map[int][]int{20: {153}, 40: {157, 161}, 80: {36, 40, 44, 48}}
+	if len(report.Recommendations) != len(want) {
+		t.Fatalf("got %d recommendations, want %d", len(report.Recommendations), len(want))
+	}
+	for _, p := range report.Recommendations {
+		if !reflect.DeepEqual(p.Channels, want[p.Width]) {
+			t.Errorf("%d MHz: channels %v, want %v", p.Width, p.Channels, want[p.Width])
+		}
+	}
+
+	// AUTO-BW lets the 40 MHz rule carry an 80 MHz block
+	reg.Rules[1].Flags = []string{"AUTO-BW"}
+	for _, p := range Analyze(fiveGHz, nil, reg).Recommendations {
+		if p.Width == 80 && !reflect.DeepEqual(p.Channels, []int{149, 153, 157, 161}) {
+			t.Errorf("AUTO-BW: 80 MHz channels %v, want [149 153 157 161]", p.Channels)
+		}
+	}
+
+	// The world domain is a fallback and restricts nothing
+	report = Analyze(fiveGHz, nil, &scanner.RegDomain{Country: "00"})
+	if len(report.Channels) != len(candidates[channel.Band5G]) {
+		t.Errorf("world domain: %d channels scored, want %d", len(report.Channels), len(candidates[channel.Band5G]))
+	}
+}
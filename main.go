@@ -4,33 +4,101 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 
 	"wifiscanner/scanner"
 	"wifiscanner/ui"
 )
 
-func main() {
-	demo := flag.Bool("demo", false, "Run with simulated network data (no root required)")
-	iface := flag.String("interface", "", "Wireless interface (auto-detected if omitted)")
-	flag.StringVar(iface, "i", "", "Wireless interface (shorthand)")
-	flag.Parse()
+// scanOptions are the flags shared by the TUI and the subcommands.
+type scanOptions struct {
+	demo        *bool
+	backendName *string
+	replay      *string
+	speed       *float64
+	record      *string
+	iface       *string
+	allIfaces   *bool
+}
+
+func addScanFlags(fs *flag.FlagSet) *scanOptions {
+	o := &scanOptions{
+		demo:        fs.Bool("demo", false, "Run with simulated network data (no root required)"),
+		backendName: fs.String("backend", "iw", "Scan backend: "+strings.Join(scanner.BackendNames(), ", ")),
+		replay:      fs.String("replay", "", "Replay recorded iw scan captures from a file or directory"),
+		speed:       fs.Float64("speed", 1, "Replay speed multiplier (with --replay)"),
+		record:      fs.String("record", "", "Record raw and parsed scan results to a directory"),
+		iface:       fs.String("interface", "", "Wireless interface(s), comma-separated (auto-detected if omitted)"),
+		allIfaces:   fs.Bool("all-interfaces", false, "Scan every detected wireless interface in parallel"),
+	}
+	fs.StringVar(o.iface, "i", "", "Wireless interface(s) (shorthand)")
+	return o
+}
+
+// newScanner builds the Scanner the flags describe, exiting with a message
+// on any setup error.
+func (o *scanOptions) newScanner() *scanner.Scanner {
+	if *o.demo {
+		*o.backendName = "demo"
+	}
+
+	var backend scanner.Backend
+	var err error
+	if *o.replay != "" {
+		backend, err = scanner.NewReplayBackend(*o.replay, *o.speed)
+	} else {
+		backend, err = scanner.NewBackend(*o.backendName)
+	}
+	if err == nil && *o.record != "" {
+		backend, err = scanner.NewRecordingBackend(backend, *o.record)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n  [!] %v\n\n", err)
+		os.Exit(1)
+	}
 
-	if !*demo && os.Geteuid() != 0 {
+	if backend.Capabilities().Has(scanner.CapNeedsRoot) && os.Geteuid() != 0 {
 		fmt.Println()
 		fmt.Println("  [!] SPECTR//SCAN requires root privileges for live WiFi scanning.")
 		fmt.Println("  [>] Run with:  sudo go run .")
-		fmt.Println("  [>] Or try:    go run . --demo")
+		fmt.Println("  [>] Or try:    go run . --backend nmcli")
+		fmt.Println("  [>]            go run . --demo")
 		fmt.Println()
 		os.Exit(1)
 	}
 
-	s, err := scanner.New(*iface, *demo)
+	var ifaces []string
+	for _, name := range strings.Split(*o.iface, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			ifaces = append(ifaces, name)
+		}
+	}
+	if *o.allIfaces {
+		ifaces, err = scanner.DetectInterfaces(backend)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "\n  [!] no wireless interface found: %v\n\n", err)
+			os.Exit(1)
+		}
+	}
+
+	s, err := scanner.New(ifaces, backend)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "\n  [!] %v\n\n", err)
 		os.Exit(1)
 	}
+	return s
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "recommend" {
+		runRecommend(os.Args[2:])
+		return
+	}
+
+	opts := addScanFlags(flag.CommandLine)
+	flag.Parse()
 
-	app := ui.New(s)
+	app := ui.New(opts.newScanner())
 	if err := app.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "\n  [!] UI error: %v\n\n", err)
 		os.Exit(1)
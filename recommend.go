@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"wifiscanner/analysis"
+	"wifiscanner/scanner"
+)
+
+// runRecommend implements the "recommend" subcommand: scan a few times,
+// then print the channel analysis as JSON.
+func runRecommend(args []string) {
+	fs := flag.NewFlagSet("recommend", flag.ExitOnError)
+	opts := addScanFlags(fs)
+	scans := fs.Int("scans", 3, "Number of scans to aggregate")
+	interval := fs.Duration("interval", 5*time.Second, "Delay between scans")
+	fs.Parse(args)
+
+	s := opts.newScanner()
+	session := scanner.NewSession()
+	latest := make(map[string]scanner.Network)
+	var order []string
+
+	for i := 0; i < *scans; i++ {
+		if i > 0 && !s.Backend.Capabilities().Has(scanner.CapPaced) {
+			time.Sleep(*interval)
+		}
+		networks, err := s.Scan()
+		if errors.Is(err, scanner.ErrReplayDone) {
+			break
+		}
+		if err != nil && len(networks) == 0 {
+			fmt.Fprintf(os.Stderr, "\n  [!] scan failed: %v\n\n", err)
+			os.Exit(1)
+		}
+		session.Update(networks)
+		for _, n := range networks {
+			if _, ok := latest[n.BSSID]; !ok {
+				order = append(order, n.BSSID)
+			}
+			latest[n.BSSID] = n
+		}
+	}
+
+	// Every BSSID seen in any scan counts, weighted by its persistence
+	networks := make([]scanner.Network, 0, len(order))
+	for _, bssid := range order {
+		networks = append(networks, latest[bssid])
+	}
+
+	// Backends that cannot report the domain get unrestricted advice
+	reg, _ := s.Regulatory()
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(analysis.Analyze(networks, session, reg)); err != nil {
+		fmt.Fprintf(os.Stderr, "\n  [!] %v\n\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,285 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Attribute is one named property decoded from an information element,
+// such as a capability flag or the hostname a controller-managed AP
+// advertises. Flags have an empty Value.
+type Attribute struct {
+	Source string // element it came from, e.g. "WMM" or "Aruba"
+	Name   string
+	Value  string
+}
+
+const (
+	ieCiscoCCX1 = 133 // Cisco CCX1 CKIP + device name
+
+	ouiCisco  = 0x004096
+	ouiAruba  = 0x000B86
+	ouiApple  = 0x0017F2
+	ouiRuckus = 0x001392
+	ouiMeraki = 0x00180A
+
+	msTypeWMM       = 2
+	ciscoTypeCCXVer = 3
+	arubaTypeAPName = 3
+)
+
+// attrAPName is the attribute name decoders use for an advertised AP
+// hostname.
+const attrAPName = "AP name"
+
+// attributeDecoder turns an element body into attributes. Vendor decoders
+// get the body after the OUI, starting at the vendor type byte.
+type attributeDecoder func(data []byte) []Attribute
+
+type registeredDecoder struct {
+	source string
+	decode attributeDecoder
+}
+
+// elementDecoders and vendorDecoders map element IDs and vendor OUIs to
+// their decoders.
+var (
+	elementDecoders = map[byte]registeredDecoder{}
+	vendorDecoders  = map[uint32]registeredDecoder{}
+)
+
+// registerElement adds a decoder for an element ID. Called from init.
+func registerElement(id byte, source string, decode attributeDecoder) {
+	elementDecoders[id] = registeredDecoder{source, decode}
+}
+
+// registerVendor adds a decoder for a vendor OUI. Called from init.
+func registerVendor(oui uint32, source string, decode attributeDecoder) {
+	vendorDecoders[oui] = registeredDecoder{source, decode}
+}
+
+func init() {
+	registerElement(ieExtCapabilities, "Extended Capabilities", decodeExtCapabilities)
+	registerElement(ieCiscoCCX1, "Cisco CCX1", decodeCiscoCCX1)
+
+	registerVendor(ouiMicrosoft, "WMM", decodeWMM)
+	registerVendor(ouiCisco, "Cisco", decodeCisco)
+	registerVendor(ouiAruba, "Aruba", decodeAruba)
+	registerVendor(ouiApple, "Apple", decodeVendorType)
+	registerVendor(ouiRuckus, "Ruckus", decodeVendorType)
+	registerVendor(ouiMeraki, "Meraki", decodeVendorType)
+}
+
+// decodeAttributes runs the registered decoders over elems, in element
+// order.
+func decodeAttributes(elems []element) []Attribute {
+	var attrs []Attribute
+	for _, e := range elems {
+		d, ok := elementDecoders[e.ID]
+		data := e.Data
+		if oui, _, vendor := e.vendorOUI(); vendor {
+			d, ok = vendorDecoders[oui]
+			data = e.Data[3:]
+		}
+		if !ok {
+			continue
+		}
+		for _, a := range d.decode(data) {
+			a.Source = d.source
+			attrs = append(attrs, a)
+		}
+	}
+	return attrs
+}
+
+// apName returns the first advertised AP hostname in attrs.
+func apName(attrs []Attribute) string {
+	for _, a := range attrs {
+		if a.Name == attrAPName {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// extCapNames names the Extended Capabilities bits worth reporting, using
+// the names iw prints.
+var extCapNames = []struct {
+	bit  int
+	name string
+}{
+	{0, "HT Information Exchange Supported"},
+	{2, "Extended Channel Switching"},
+	{19, "BSS Transition"},
+	{22, "Multiple BSSID"},
+	{23, "Timing Measurement"},
+	{31, "Interworking"},
+	{32, "QoS Map"},
+	{46, "WNM-Notification"},
+	{62, "Operating Mode Notification"},
+	{70, "FTM Responder"},
+	{71, "FTM Initiator"},
+	{77, "TWT Requester Support"},
+	{78, "TWT Responder Support"},
+	{81, "SAE Password Identifiers In Use"},
+	{82, "SAE Passwords Used Exclusively"},
+	{84, "Beacon Protection Enabled"},
+}
+
+func decodeExtCapabilities(data []byte) []Attribute {
+	var attrs []Attribute
+	for _, c := range extCapNames {
+		if extCapBit(data, c.bit) {
+			attrs = append(attrs, Attribute{Name: c.name})
+		}
+	}
+	return attrs
+}
+
+// decodeCiscoCCX1 reads the AP name Cisco controllers put in element 133:
+// 10 unknown bytes, a NUL-padded 16-byte name, then the client count.
+func decodeCiscoCCX1(data []byte) []Attribute {
+	if len(data) < 26 {
+		return nil
+	}
+	var attrs []Attribute
+	if name := wpsString(data[10:26]); name != "" {
+		attrs = append(attrs, Attribute{Name: attrAPName, Value: name})
+	}
+	if len(data) > 26 {
+		attrs = append(attrs, Attribute{Name: "Clients", Value: strconv.Itoa(int(data[26]))})
+	}
+	return attrs
+}
+
+// wmmACs are the WMM access categories in ACI order.
+var wmmACs = [4]string{"BE", "BK", "VI", "VO"}
+
+// decodeWMM reads a WMM Parameter or Information element, formatted like
+// iw. WPA and WPS share the Microsoft OUI and are decoded elsewhere.
+func decodeWMM(data []byte) []Attribute {
+	if len(data) < 4 || data[0] != msTypeWMM {
+		return nil
+	}
+	attrs := []Attribute{{Name: "Version", Value: strconv.Itoa(int(data[2]))}}
+	if data[3]&0x80 != 0 {
+		attrs = append(attrs, Attribute{Name: "U-APSD"})
+	}
+	// Parameter elements add a reserved byte and four AC records
+	if data[1] != 1 || len(data) < 5+4*4 {
+		return attrs
+	}
+	for rec := data[5:]; len(rec) >= 4; rec = rec[4:] {
+		aci := rec[0] >> 5 & 0x03
+		v := ""
+		if rec[0]&0x10 != 0 {
+			v = "acm "
+		}
+		v += fmt.Sprintf("CW %d-%d", 1<<(rec[1]&0x0F)-1, 1<<(rec[1]>>4)-1)
+		if aifsn := rec[0] & 0x0F; aifsn != 0 {
+			v += fmt.Sprintf(", AIFSN %d", aifsn)
+		}
+		if txop := binary.LittleEndian.Uint16(rec[2:]); txop != 0 {
+			v += fmt.Sprintf(", TXOP %d usec", int(txop)*32)
+		}
+		attrs = append(attrs, Attribute{Name: wmmACs[aci], Value: v})
+	}
+	return attrs
+}
+
+// decodeCisco reads the Aironet vendor element: the CCX version, or the
+// element type for extensions without a decoder.
+func decodeCisco(data []byte) []Attribute {
+	if len(data) >= 2 && data[0] == ciscoTypeCCXVer {
+		return []Attribute{{Name: "CCX version", Value: strconv.Itoa(int(data[1]))}}
+	}
+	return decodeVendorType(data)
+}
+
+// decodeAruba reads the AP name Aruba controllers advertise: type 3, one
+// unknown byte, then the name.
+func decodeAruba(data []byte) []Attribute {
+	if len(data) > 2 && data[0] == arubaTypeAPName {
+		if name := wpsString(data[2:]); name != "" {
+			return []Attribute{{Name: attrAPName, Value: name}}
+		}
+	}
+	return decodeVendorType(data)
+}
+
+// decodeVendorType records that a vendor element is present, for vendors
+// whose elements carry nothing decoded.
+func decodeVendorType(data []byte) []Attribute {
+	if len(data) < 1 {
+		return nil
+	}
+	return []Attribute{{Name: "Element", Value: fmt.Sprintf("type %d, %d bytes", data[0], len(data)-1)}}
+}
+
+// iwUnknownRe matches the elements 'iw scan -u' has no printer for.
+var iwUnknownRe = regexp.MustCompile(`(?m)^\s*Unknown IE \((\d+)\):([0-9a-fA-F ]*)$`)
+
+// iwUnknownElements rebuilds raw elements from iw's "Unknown IE" dumps.
+func iwUnknownElements(block string) []element {
+	var elems []element
+	for _, m := range iwUnknownRe.FindAllStringSubmatch(block, -1) {
+		id, err := strconv.ParseUint(m[1], 10, 8)
+		if err != nil {
+			continue
+		}
+		data, err := hex.DecodeString(strings.ReplaceAll(m[2], " ", ""))
+		if err != nil {
+			continue
+		}
+		elems = append(elems, element{ID: byte(id), Data: data})
+	}
+	return elems
+}
+
+// parseAttributes collects attributes from an iw scan block. iw decodes
+// Extended Capabilities and WMM itself, so those are read from its text;
+// vendor and unknown elements are decoded from the -u hex dumps.
+func parseAttributes(block string) []Attribute {
+	var attrs []Attribute
+
+	if lines, ok := iwSection(block, "Extended capabilities"); ok {
+		set := make(map[string]bool, len(lines))
+		for _, l := range lines {
+			set[l] = true
+		}
+		for _, c := range extCapNames {
+			if set[c.name] {
+				attrs = append(attrs, Attribute{Source: "Extended Capabilities", Name: c.name})
+			}
+		}
+	}
+
+	if lines, ok := iwSection(block, "WMM"); ok {
+		for _, l := range lines {
+			a := Attribute{Source: "WMM"}
+			switch k, v, _ := strings.Cut(l, ":"); {
+			case strings.HasPrefix(l, "Parameter version "):
+				a.Name, a.Value = "Version", strings.TrimPrefix(l, "Parameter version ")
+			case k == "information":
+				// Information elements are dumped as hex: version, QoS info
+				info, _ := hex.DecodeString(strings.ReplaceAll(v, " ", ""))
+				attrs = append(attrs, decodeWMM(append([]byte{msTypeWMM, 0}, info...))...)
+				continue
+			case l == "u-APSD":
+				a.Name = "U-APSD"
+			case len(k) == 2 && v != "":
+				a.Name, a.Value = k, strings.TrimSpace(v)
+			default:
+				continue
+			}
+			attrs = append(attrs, a)
+		}
+	}
+
+	elems := append(iwUnknownElements(block), iwVendorElements(block)...)
+	return append(attrs, decodeAttributes(elems)...)
+}
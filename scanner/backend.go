@@ -0,0 +1,94 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scanTimeout bounds how long a backend waits for a triggered scan to finish.
+const scanTimeout = 15 * time.Second
+
+// Capabilities is a bit set describing what a Backend needs and provides.
+type Capabilities uint
+
+const (
+	// CapNeedsRoot means the backend must run as root to scan.
+	CapNeedsRoot Capabilities = 1 << iota
+	// CapActiveScan means the backend can trigger a fresh scan rather than
+	// only reading cached results.
+	CapActiveScan
+	// CapPaced means Scan blocks until the backend's next result is due, so
+	// callers should scan back-to-back instead of on a timer.
+	CapPaced
+	// CapRawIEs means Scan fills Network.IEs with the information elements
+	// as received.
+	CapRawIEs
+)
+
+// Has reports whether every capability in c2 is set in c.
+func (c Capabilities) Has(c2 Capabilities) bool {
+	return c&c2 == c2
+}
+
+// Backend is a source of scan results. Scanner delegates every scan to one.
+type Backend interface {
+	// Name returns the identifier used to select the backend (e.g. "iw").
+	Name() string
+	// Scan returns the networks currently visible on iface.
+	Scan(iface string) ([]Network, error)
+	// Capabilities describes what the backend needs and provides.
+	Capabilities() Capabilities
+}
+
+// RawScanner is implemented by backends that can return the unparsed output
+// a scan was decoded from, so it can be recorded and replayed later.
+type RawScanner interface {
+	ScanRaw(iface string) (raw []byte, networks []Network, err error)
+}
+
+// InterfaceLister is implemented by backends that can enumerate the
+// wireless interfaces they are able to scan on.
+type InterfaceLister interface {
+	Interfaces() ([]string, error)
+}
+
+// Surveyor is implemented by backends that can report per-channel survey
+// data (noise floor and airtime counters) alongside a scan.
+type Surveyor interface {
+	Survey(iface string) ([]ChannelSurvey, error)
+}
+
+// RegulatoryReporter is implemented by backends that can report the local
+// regulatory domain.
+type RegulatoryReporter interface {
+	Regulatory() (*RegDomain, error)
+}
+
+// backends maps backend names to their constructors.
+var backends = map[string]func() Backend{}
+
+// register makes a backend selectable by name. Called from init.
+func register(name string, ctor func() Backend) {
+	backends[name] = ctor
+}
+
+// NewBackend returns the backend registered under name.
+func NewBackend(name string) (Backend, error) {
+	ctor, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend %q (available: %s)", name, strings.Join(BackendNames(), ", "))
+	}
+	return ctor(), nil
+}
+
+// BackendNames returns the names of all registered backends, sorted.
+func BackendNames() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
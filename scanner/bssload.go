@@ -0,0 +1,43 @@
+package scanner
+
+import "encoding/binary"
+
+const ieBSSLoad = 11
+
+// BSSLoad is the AP-reported load from the BSS Load element.
+type BSSLoad struct {
+	StationCount      int // associated stations
+	Utilisation       int // channel busy share as reported, 0-255
+	AdmissionCapacity int // remaining admission capacity, units of 32 µs/s
+}
+
+// UtilisationPercent converts the 0-255 channel utilisation to percent.
+func (l BSSLoad) UtilisationPercent() float64 {
+	return float64(l.Utilisation) * 100 / 255
+}
+
+// decodeBSSLoad parses a BSS Load element body.
+func decodeBSSLoad(data []byte) *BSSLoad {
+	if len(data) < 5 {
+		return nil
+	}
+	return &BSSLoad{
+		StationCount:      int(binary.LittleEndian.Uint16(data)),
+		Utilisation:       int(data[2]),
+		AdmissionCapacity: int(binary.LittleEndian.Uint16(data[3:])),
+	}
+}
+
+// parseBSSLoad reads the "BSS Load" section iw prints.
+func parseBSSLoad(block string) *BSSLoad {
+	lines, ok := iwSection(block, "BSS Load")
+	if !ok {
+		return nil
+	}
+	fields := iwFields(lines)
+	return &BSSLoad{
+		StationCount:      iwLeadingInt(fields["station count"]),
+		Utilisation:       iwLeadingInt(fields["channel utilisation"]),
+		AdmissionCapacity: iwLeadingInt(fields["available admission capacity"]),
+	}
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeBSSLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want *BSSLoad
+	}{
+		{"typical", []byte{0x03, 0x00, 0x2f, 0x12, 0x7a}, &BSSLoad{StationCount: 3, Utilisation: 47, AdmissionCapacity: 31250}},
+		{"little endian", []byte{0x2c, 0x01, 0xff, 0x00, 0x00}, &BSSLoad{StationCount: 300, Utilisation: 255}},
+		{"truncated", []byte{0x03, 0x00, 0x2f, 0x12}, nil},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeBSSLoad(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeBSSLoad() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBSSLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  *BSSLoad
+	}{
+		{
+			"iw",
+			`BSS a4:2b:8c:01:02:03(on wlan0)
+	SSID: HomeNet
+	BSS Load:
+		 * station count: 3
+		 * channel utilisation: 47/255
+		 * available admission capacity: 31250 [*32us]
+	HT capabilities:
+`,
+			&BSSLoad{StationCount: 3, Utilisation: 47, AdmissionCapacity: 31250},
+		},
+		{"absent", "BSS a4:2b:8c:01:02:03(on wlan0)\n\tSSID: HomeNet\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBSSLoad(tt.block); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBSSLoad() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBSSLoadUtilisationPercent(t *testing.T) {
+	for _, tt := range []struct {
+		raw     int
+		percent float64
+	}{{0, 0}, {51, 20}, {255, 100}} {
+		if got := (BSSLoad{Utilisation: tt.raw}).UtilisationPercent(); got != tt.percent {
+			t.Errorf("UtilisationPercent(%d) = %g, want %g", tt.raw, got, tt.percent)
+		}
+	}
+}
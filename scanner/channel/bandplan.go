@@ -0,0 +1,241 @@
+package channel
+
+// Band identifies a frequency band.
+type Band int
+
+const (
+	BandUnknown Band = iota
+	BandS1G          // sub-1 GHz, 802.11ah
+	Band2G
+	Band49G // 4.9 GHz public safety and Japanese 802.11j channels
+	Band5G
+	Band6G
+	Band60G // 802.11ad/ay
+)
+
+var bandNames = map[Band][2]string{
+	BandS1G: {"sub-1 GHz", "S1G"},
+	Band2G:  {"2.4 GHz", "2.4G"},
+	Band49G: {"4.9 GHz", "4.9G"},
+	Band5G:  {"5 GHz", "5G"},
+	Band6G:  {"6 GHz", "6G"},
+	Band60G: {"60 GHz", "60G"},
+}
+
+// String returns the band name, e.g. "5 GHz".
+func (b Band) String() string {
+	if n, ok := bandNames[b]; ok {
+		return n[0]
+	}
+	return "unknown"
+}
+
+// Short returns the compact band label, e.g. "5G".
+func (b Band) Short() string {
+	if n, ok := bandNames[b]; ok {
+		return n[1]
+	}
+	return "?"
+}
+
+// plan is one linear run of channel numbers: channel ch is centered on
+// base + ch*spacing. Frequencies are in kHz so that the half-MHz sub-1 GHz
+// raster fits.
+type plan struct {
+	band        Band
+	base        int // kHz
+	spacing     int // kHz per channel number
+	first, last int // channel numbers covered
+	width       int // nominal channel width in kHz, sets the band edges
+	bonded      bool
+}
+
+// plans is the band plan, lowest frequency first. Where a band has
+// exceptions to its raster (2.4 GHz channel 14, 6 GHz channel 2) the
+// exception comes first. Bonded runs only convert channel to frequency:
+// their centers coincide with primary channels or fall between them.
+var plans = []plan{
+	// 802.11ah US plan: 902 MHz + 0.5 MHz per channel number. Other
+	// regions number their sub-1 GHz channels independently.
+	{band: BandS1G, base: 902000, spacing: 500, first: 1, last: 51, width: 1000},
+
+	{band: Band2G, base: 2414000, spacing: 5000, first: 14, last: 14, width: 20000},
+	{band: Band2G, base: 2407000, spacing: 5000, first: 1, last: 13, width: 20000},
+
+	{band: Band49G, base: 4000000, spacing: 5000, first: 182, last: 198, width: 20000},
+
+	{band: Band5G, base: 5000000, spacing: 5000, first: 7, last: 16, width: 20000}, // Japan
+	{band: Band5G, base: 5000000, spacing: 5000, first: 32, last: 177, width: 20000},
+
+	{band: Band6G, base: 5925000, spacing: 5000, first: 2, last: 2, width: 20000},
+	{band: Band6G, base: 5950000, spacing: 5000, first: 1, last: 233, width: 20000},
+
+	// 2.16 GHz channels, then 802.11ay channel bonding of 2, 3 and 4 channels
+	{band: Band60G, base: 56160000, spacing: 2160000, first: 1, last: 6, width: 2160000},
+	{band: Band60G, base: 39960000, spacing: 2160000, first: 9, last: 13, width: 4320000, bonded: true},
+	{band: Band60G, base: 23760000, spacing: 2160000, first: 17, last: 20, width: 6480000, bonded: true},
+	{band: Band60G, base: 7560000, spacing: 2160000, first: 25, last: 27, width: 8640000, bonded: true},
+}
+
+func (p plan) center(ch int) int {
+	return p.base + ch*p.spacing
+}
+
+// covers reports whether khz lies within the plan's outermost channels.
+func (p plan) covers(khz int) bool {
+	return khz >= p.center(p.first)-p.width/2 && khz <= p.center(p.last)+p.width/2
+}
+
+// BandOf returns the band a frequency (MHz) falls in.
+func BandOf(freq int) Band {
+	khz := freq * 1000
+	for _, p := range plans {
+		if p.covers(khz) {
+			return p.band
+		}
+	}
+	return BandUnknown
+}
+
+// Number converts a channel center frequency in MHz to its channel number,
+// or 0 if it is not on a channel raster. Sub-1 GHz channels centered on a
+// half MHz need NumberKHz.
+func Number(freq int) int {
+	return NumberKHz(freq * 1000)
+}
+
+// NumberKHz converts a channel center frequency in kHz to its channel
+// number, or 0 if it is not on a channel raster.
+func NumberKHz(khz int) int {
+	for _, p := range plans {
+		if p.bonded || !p.covers(khz) || (khz-p.base)%p.spacing != 0 {
+			continue
+		}
+		if ch := (khz - p.base) / p.spacing; ch >= p.first && ch <= p.last {
+			return ch
+		}
+	}
+	return 0
+}
+
+// Frequency converts a channel number in band b to its center frequency in
+// MHz, or 0 if the band has no such channel.
+func Frequency(b Band, ch int) int {
+	return FrequencyKHz(b, ch) / 1000
+}
+
+// FrequencyKHz converts a channel number in band b to its center frequency
+// in kHz, or 0 if the band has no such channel.
+func FrequencyKHz(b Band, ch int) int {
+	for _, p := range plans {
+		if p.band == b && ch >= p.first && ch <= p.last {
+			return p.center(ch)
+		}
+	}
+	return 0
+}
+
+// OperatingClass is a global operating class (IEEE 802.11 Annex E, table
+// E-4). Sub-1 GHz and 4.9 GHz channels only have regional classes and are
+// not listed.
+type OperatingClass struct {
+	Class    int
+	Band     Band
+	Width    int   // MHz; 160 for 80+80, 2160 multiples on 60 GHz
+	Offset   int   // 40 MHz secondary channel: 1 above, -1 below
+	Channels []int // primary channels for 20 and 40 MHz, else block centers
+}
+
+var operatingClasses = []OperatingClass{
+	{81, Band2G, 20, 0, Range(1, 13, 1)},
+	{82, Band2G, 20, 0, []int{14}},
+	{83, Band2G, 40, 1, Range(1, 9, 1)},
+	{84, Band2G, 40, -1, Range(5, 13, 1)},
+
+	{115, Band5G, 20, 0, Range(36, 48, 4)},
+	{116, Band5G, 40, 1, []int{36, 44}},
+	{117, Band5G, 40, -1, []int{40, 48}},
+	{118, Band5G, 20, 0, Range(52, 64, 4)},
+	{119, Band5G, 40, 1, []int{52, 60}},
+	{120, Band5G, 40, -1, []int{56, 64}},
+	{121, Band5G, 20, 0, Range(100, 144, 4)},
+	{122, Band5G, 40, 1, Range(100, 140, 8)},
+	{123, Band5G, 40, -1, Range(104, 144, 8)},
+	{124, Band5G, 20, 0, Range(149, 161, 4)},
+	{125, Band5G, 20, 0, Range(149, 177, 4)},
+	{126, Band5G, 40, 1, Range(149, 173, 8)},
+	{127, Band5G, 40, -1, Range(153, 177, 8)},
+	{128, Band5G, 80, 0, []int{42, 58, 106, 122, 138, 155, 171}},
+	{129, Band5G, 160, 0, []int{50, 114, 163}},
+	{130, Band5G, 160, 0, []int{42, 58, 106, 122, 138, 155, 171}}, // 80+80
+
+	{131, Band6G, 20, 0, Range(1, 233, 4)},
+	{132, Band6G, 40, 0, Range(3, 227, 8)},
+	{133, Band6G, 80, 0, Range(7, 215, 16)},
+	{134, Band6G, 160, 0, Range(15, 207, 32)},
+	{135, Band6G, 160, 0, Range(7, 215, 16)}, // 80+80
+	{136, Band6G, 20, 0, []int{2}},
+	{137, Band6G, 320, 0, Range(31, 191, 32)},
+
+	{180, Band60G, 2160, 0, Range(1, 6, 1)},
+	{181, Band60G, 4320, 0, Range(9, 13, 1)},
+	{182, Band60G, 6480, 0, Range(17, 20, 1)},
+	{183, Band60G, 8640, 0, Range(25, 27, 1)},
+}
+
+// Range lists channel numbers from first to last in steps of step, e.g.
+// Range(36, 48, 4) for the UNII-1 20 MHz channels.
+func Range(first, last, step int) []int {
+	var chans []int
+	for ch := first; ch <= last; ch += step {
+		chans = append(chans, ch)
+	}
+	return chans
+}
+
+// Class looks up a global operating class by number.
+func Class(class int) (OperatingClass, bool) {
+	for _, oc := range operatingClasses {
+		if oc.Class == class {
+			return oc, true
+		}
+	}
+	return OperatingClass{}, false
+}
+
+// Has reports whether the class includes channel ch.
+func (oc OperatingClass) Has(ch int) bool {
+	for _, c := range oc.Channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+// OperatingClass returns the global operating class of the span, or 0 if
+// none matches.
+func (s Span) OperatingClass() int {
+	band := BandOf(s.Primary)
+	ch, offset := Number(s.Primary), 0
+	switch {
+	case s.Width > 40 || (s.Width == 40 && band == Band6G):
+		// These classes list block centers rather than primaries
+		ch = Number(s.Center)
+	case s.Width == 40 && s.Secondary < s.Primary:
+		offset = -1
+	case s.Width == 40:
+		offset = 1
+	}
+
+	for _, oc := range operatingClasses {
+		if oc.Band != band || oc.Width != s.Width || oc.Offset != offset || !oc.Has(ch) {
+			continue
+		}
+		// 80+80 and contiguous 160 MHz share a width
+		if split := oc.Class == 130 || oc.Class == 135; split == (s.Center2 != 0) {
+			return oc.Class
+		}
+	}
+	return 0
+}
@@ -0,0 +1,236 @@
+package channel
+
+import "testing"
+
+func TestChannelFrequency(t *testing.T) {
+	tests := []struct {
+		band Band
+		ch   int
+		khz  int
+	}{
+		{BandS1G, 1, 902500},
+		{BandS1G, 2, 903000},
+		{BandS1G, 3, 903500},
+		{BandS1G, 51, 927500},
+
+		{Band2G, 1, 2412000},
+		{Band2G, 6, 2437000},
+		{Band2G, 13, 2472000},
+		{Band2G, 14, 2484000},
+
+		{Band49G, 182, 4910000},
+		{Band49G, 184, 4920000},
+		{Band49G, 196, 4980000},
+		{Band49G, 198, 4990000},
+
+		{Band5G, 7, 5035000},
+		{Band5G, 16, 5080000},
+		{Band5G, 36, 5180000},
+		{Band5G, 100, 5500000},
+		{Band5G, 144, 5720000},
+		{Band5G, 149, 5745000},
+		{Band5G, 165, 5825000},
+		{Band5G, 169, 5845000},
+		{Band5G, 173, 5865000},
+		{Band5G, 177, 5885000},
+
+		{Band6G, 1, 5955000},
+		{Band6G, 2, 5935000},
+		{Band6G, 5, 5975000},
+		{Band6G, 37, 6135000},
+		{Band6G, 233, 7115000},
+
+		{Band60G, 1, 58320000},
+		{Band60G, 2, 60480000},
+		{Band60G, 3, 62640000},
+		{Band60G, 4, 64800000},
+		{Band60G, 5, 66960000},
+		{Band60G, 6, 69120000},
+	}
+	for _, tt := range tests {
+		if got := FrequencyKHz(tt.band, tt.ch); got != tt.khz {
+			t.Errorf("FrequencyKHz(%s, %d) = %d, want %d", tt.band, tt.ch, got, tt.khz)
+		}
+		if got := NumberKHz(tt.khz); got != tt.ch {
+			t.Errorf("NumberKHz(%d) = %d, want %d", tt.khz, got, tt.ch)
+		}
+		if got := BandOf(tt.khz / 1000); got != tt.band {
+			t.Errorf("BandOf(%d) = %s, want %s", tt.khz/1000, got, tt.band)
+		}
+	}
+}
+
+func TestChannelRoundTrip(t *testing.T) {
+	for _, p := range plans {
+		if p.bonded {
+			continue
+		}
+		for ch := p.first; ch <= p.last; ch++ {
+			khz := FrequencyKHz(p.band, ch)
+			if khz == 0 {
+				t.Errorf("FrequencyKHz(%s, %d) = 0", p.band, ch)
+				continue
+			}
+			if got := NumberKHz(khz); got != ch {
+				t.Errorf("%s channel %d: NumberKHz(%d) = %d", p.band, ch, khz, got)
+			}
+		}
+	}
+}
+
+func TestBondedChannels(t *testing.T) {
+	tests := []struct {
+		ch  int
+		mhz int
+	}{
+		{9, 59400},
+		{13, 68040},
+		{17, 60480},
+		{20, 66960},
+		{25, 61560},
+		{27, 65880},
+	}
+	for _, tt := range tests {
+		if got := Frequency(Band60G, tt.ch); got != tt.mhz {
+			t.Errorf("Frequency(60 GHz, %d) = %d, want %d", tt.ch, got, tt.mhz)
+		}
+	}
+}
+
+func TestOffRaster(t *testing.T) {
+	tests := []struct {
+		band Band
+		ch   int
+	}{
+		{Band2G, 0},
+		{Band2G, 15},
+		{Band5G, 178},
+		{Band6G, 234},
+		{Band49G, 181},
+		{Band60G, 7},
+		{BandUnknown, 1},
+	}
+	for _, tt := range tests {
+		if got := Frequency(tt.band, tt.ch); got != 0 {
+			t.Errorf("Frequency(%s, %d) = %d, want 0", tt.band, tt.ch, got)
+		}
+	}
+	for _, mhz := range []int{2400, 2413, 5000, 5900, 5950, 7200, 59400} {
+		if got := Number(mhz); got != 0 {
+			t.Errorf("Number(%d) = %d, want 0", mhz, got)
+		}
+	}
+}
+
+func TestBandOf(t *testing.T) {
+	tests := []struct {
+		mhz  int
+		band Band
+	}{
+		{901, BandUnknown},
+		{902, BandS1G},
+		{928, BandS1G},
+		{929, BandUnknown},
+
+		{2401, BandUnknown},
+		{2402, Band2G},
+		{2494, Band2G},
+		{2495, BandUnknown},
+
+		{4899, BandUnknown},
+		{4900, Band49G},
+		{5000, Band49G},
+		{5024, BandUnknown},
+
+		{5025, Band5G},
+		{5090, Band5G},
+		{5091, BandUnknown},
+		{5149, BandUnknown},
+		{5150, Band5G},
+		{5895, Band5G},
+		{5896, BandUnknown},
+
+		{5924, BandUnknown},
+		{5925, Band6G},
+		{5945, Band6G},
+		{7125, Band6G},
+		{7126, BandUnknown},
+
+		{57239, BandUnknown},
+		{57240, Band60G},
+		{70200, Band60G},
+		{70201, BandUnknown},
+	}
+	for _, tt := range tests {
+		if got := BandOf(tt.mhz); got != tt.band {
+			t.Errorf("BandOf(%d) = %s, want %s", tt.mhz, got, tt.band)
+		}
+	}
+}
+
+func TestSpanOperatingClass(t *testing.T) {
+	tests := []struct {
+		name  string
+		span  Span
+		class int
+	}{
+		{"2.4 GHz 20", New(2437, 20, 0, 0, 0), 81},
+		{"2.4 GHz ch14", New(2484, 20, 0, 0, 0), 82},
+		{"2.4 GHz 40 above", New(2412, 40, 1, 0, 0), 83},
+		{"2.4 GHz 40 below", New(2462, 40, -1, 0, 0), 84},
+
+		{"5 GHz 20 UNII-1", New(5180, 20, 0, 0, 0), 115},
+		{"5 GHz 20 UNII-2", New(5260, 20, 0, 0, 0), 118},
+		{"5 GHz 20 UNII-2e", New(5500, 20, 0, 0, 0), 121},
+		{"5 GHz 20 UNII-3", New(5745, 20, 0, 0, 0), 124},
+		{"5 GHz 20 ch165", New(5825, 20, 0, 0, 0), 125},
+		{"5 GHz 20 ch177", New(5885, 20, 0, 0, 0), 125},
+		{"5 GHz 40 above", New(5180, 40, 1, 0, 0), 116},
+		{"5 GHz 40 below", New(5200, 40, -1, 0, 0), 117},
+		{"5 GHz 40 above UNII-2e", New(5500, 40, 1, 0, 0), 122},
+		{"5 GHz 40 below UNII-3", New(5765, 40, -1, 0, 0), 127},
+		{"5 GHz 80", New(5180, 80, 0, 42, 0), 128},
+		{"5 GHz 80 aligned", New(5745, 80, 0, 0, 0), 128},
+		{"5 GHz 160", New(5180, 160, 0, 42, 50), 129},
+		{"5 GHz 160 old signalling", New(5500, 160, 0, 114, 0), 129},
+		{"5 GHz 80+80", New(5180, 160, 0, 42, 155), 130},
+
+		{"6 GHz 20", New(5955, 20, 0, 0, 0), 131},
+		{"6 GHz ch2", New(5935, 20, 0, 0, 0), 136},
+		{"6 GHz 40", New(5975, 40, 0, 0, 0), 132},
+		{"6 GHz 80", New(5955, 80, 0, 7, 0), 133},
+		{"6 GHz 160", New(6115, 160, 0, 39, 47), 134},
+		{"6 GHz 80+80", New(5955, 160, 0, 7, 39), 135},
+		{"6 GHz 320", New(6115, 320, 0, 31, 0), 137},
+		{"6 GHz 320 new signalling", New(6115, 320, 0, 47, 63), 137},
+
+		{"60 GHz", New(58320, 2160, 0, 0, 0), 180},
+
+		{"4.9 GHz has no global class", New(4940, 20, 0, 0, 0), 0},
+		{"5 GHz 40 on wrong side", New(5180, 40, -1, 0, 0), 0},
+		{"unknown", New(2300, 20, 0, 0, 0), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.span.OperatingClass(); got != tt.class {
+				t.Errorf("OperatingClass(%v) = %d, want %d", tt.span, got, tt.class)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	got := Range(36, 48, 4)
+	want := []int{36, 40, 44, 48}
+	if len(got) != len(want) {
+		t.Fatalf("Range(36, 48, 4) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Range(36, 48, 4) = %v, want %v", got, want)
+		}
+	}
+	if got := Range(5, 1, 1); len(got) != 0 {
+		t.Errorf("Range(5, 1, 1) = %v, want empty", got)
+	}
+}
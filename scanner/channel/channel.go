@@ -0,0 +1,179 @@
+// Package channel models the spectrum a BSS occupies: its primary channel,
+// operating width, secondary channel and center frequency segments, and the
+// band plan that maps channel numbers to frequencies.
+package channel
+
+import "fmt"
+
+// Span is the block of spectrum a BSS transmits on. 80+80 MHz networks
+// occupy two separate 80 MHz segments; all others are contiguous.
+type Span struct {
+	Primary   int // primary 20 MHz channel center, MHz
+	Secondary int // secondary 20 MHz channel center, MHz, 0 for 20 MHz
+	Width     int // total width in MHz: 20, 40, 80, 160 or 320
+	Center    int // center of the span (first segment for 80+80), MHz
+	Center2   int // center of the second 80 MHz segment for 80+80, else 0
+}
+
+// New builds the span of a network from its primary frequency, operating
+// width, HT secondary channel offset (1 above, -1 below) and the center
+// frequency segment channel numbers from the VHT/HE/EHT operation elements.
+// Missing segments are filled in from the standard channel plan.
+func New(primary, width, secondaryOffset, seg0, seg1 int) Span {
+	band := BandOf(primary)
+	if width < 20 {
+		width = 20
+	}
+	s := Span{Primary: primary, Width: width, Center: primary}
+	if width == 20 {
+		return s
+	}
+
+	diff := seg1 - seg0
+	if diff < 0 {
+		diff = -diff
+	}
+
+	switch {
+	case width == 40 && secondaryOffset != 0:
+		s.Center = primary + 10*secondaryOffset
+	case seg0 != 0 && seg1 != 0 && diff*5 == width/4:
+		// New-style signalling: seg0 is the primary half, seg1 the whole span
+		s.Center = Frequency(band, seg1)
+	case seg0 != 0 && seg1 != 0 && width == 160:
+		// 80+80: two non-adjacent 80 MHz segments
+		s.Width = 160
+		s.Center, s.Center2 = Frequency(band, seg0), Frequency(band, seg1)
+	case seg0 != 0 && seg1 == 0:
+		s.Center = Frequency(band, seg0)
+	default:
+		s.Center = alignedCenter(band, primary, width)
+	}
+
+	// The secondary 20 is the other half of the primary 40 MHz block
+	low := s.Center - s.segWidth()/2
+	if s.Center2 != 0 && primary > s.Center2-40 && primary < s.Center2+40 {
+		low = s.Center2 - 40
+	}
+	idx := (primary - low) / 20
+	s.Secondary = low + 10 + 20*(idx^1)
+	return s
+}
+
+// alignedCenter places a width-MHz block containing primary on the band's
+// channel raster. 2.4 GHz has no raster, so 40 MHz there extends away from
+// the nearer band edge. Bands without bonded 20 MHz channels keep the
+// primary as center.
+func alignedCenter(band Band, primary, width int) int {
+	switch band {
+	case Band2G:
+		if Number(primary) <= 7 {
+			return primary + 10
+		}
+		return primary - 10
+	case Band5G, Band6G:
+	default:
+		return primary
+	}
+
+	first := 1 // 6 GHz channels start at 1
+	if band == Band5G {
+		first = 36
+		if Number(primary) >= 149 {
+			first = 149
+		}
+	}
+	n := width / 5 // width in channel numbers
+	lowest := first + (Number(primary)-first)/n*n
+	return Frequency(band, lowest+n/2-2)
+}
+
+// segWidth is the width of one contiguous segment.
+func (s Span) segWidth() int {
+	if s.Center2 != 0 {
+		return s.Width / 2
+	}
+	return s.Width
+}
+
+// Segments returns the occupied frequency ranges as [low, high] MHz pairs.
+func (s Span) Segments() [][2]int {
+	w := s.segWidth() / 2
+	segs := [][2]int{{s.Center - w, s.Center + w}}
+	if s.Center2 != 0 {
+		segs = append(segs, [2]int{s.Center2 - w, s.Center2 + w})
+	}
+	return segs
+}
+
+// Low returns the lowest occupied frequency in MHz.
+func (s Span) Low() int {
+	low := s.Segments()[0][0]
+	for _, seg := range s.Segments() {
+		if seg[0] < low {
+			low = seg[0]
+		}
+	}
+	return low
+}
+
+// High returns the highest occupied frequency in MHz.
+func (s Span) High() int {
+	high := s.Segments()[0][1]
+	for _, seg := range s.Segments() {
+		if seg[1] > high {
+			high = seg[1]
+		}
+	}
+	return high
+}
+
+// Contains reports whether freq lies inside the occupied spectrum.
+func (s Span) Contains(freq int) bool {
+	for _, seg := range s.Segments() {
+		if freq > seg[0] && freq < seg[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Overlap returns how many MHz of spectrum s and o share.
+func (s Span) Overlap(o Span) int {
+	total := 0
+	for _, a := range s.Segments() {
+		for _, b := range o.Segments() {
+			lo, hi := a[0], a[1]
+			if b[0] > lo {
+				lo = b[0]
+			}
+			if b[1] < hi {
+				hi = b[1]
+			}
+			if hi > lo {
+				total += hi - lo
+			}
+		}
+	}
+	return total
+}
+
+// Channels lists the 20 MHz channel numbers the span covers.
+func (s Span) Channels() []int {
+	var chans []int
+	for _, seg := range s.Segments() {
+		for f := seg[0] + 10; f < seg[1]; f += 20 {
+			chans = append(chans, Number(f))
+		}
+	}
+	return chans
+}
+
+// String describes the span, e.g. "80 MHz 5170-5250".
+func (s Span) String() string {
+	if s.Center2 != 0 {
+		segs := s.Segments()
+		return fmt.Sprintf("80+80 MHz %d-%d, %d-%d", segs[0][0], segs[0][1], segs[1][0], segs[1][1])
+	}
+	return fmt.Sprintf("%d MHz %d-%d", s.Width, s.Low(), s.High())
+}
@@ -0,0 +1,55 @@
+package channel
+
+// Radar requirement of a 5 GHz channel.
+type Radar int
+
+const (
+	RadarNone    Radar = iota
+	RadarDFS           // radar detection and 60 s channel availability check
+	RadarWeather       // overlaps 5600-5650 MHz weather radar: 10 min check in ETSI
+)
+
+// String returns the badge used for the requirement.
+func (r Radar) String() string {
+	switch r {
+	case RadarDFS:
+		return "DFS"
+	case RadarWeather:
+		return "WXR"
+	default:
+		return ""
+	}
+}
+
+// radarChannels lists the 5 GHz channels that require DFS in the FCC and
+// ETSI domains (U-NII-2A and U-NII-2C).
+var radarChannels = map[int]Radar{
+	52: RadarDFS, 56: RadarDFS, 60: RadarDFS, 64: RadarDFS,
+	100: RadarDFS, 104: RadarDFS, 108: RadarDFS, 112: RadarDFS, 116: RadarDFS,
+	120: RadarWeather, 124: RadarWeather, 128: RadarWeather,
+	132: RadarDFS, 136: RadarDFS, 140: RadarDFS, 144: RadarDFS,
+}
+
+// RadarOf returns the radar requirement of the 20 MHz channel centered on
+// freq.
+func RadarOf(freq int) Radar {
+	if BandOf(freq) != Band5G {
+		return RadarNone
+	}
+	return radarChannels[Number(freq)]
+}
+
+// Radar returns the strictest radar requirement of any 20 MHz channel the
+// span covers: a 160 MHz network with its primary on 36 also occupies 52-64
+// and must run DFS.
+func (s Span) Radar() Radar {
+	r := RadarNone
+	for _, seg := range s.Segments() {
+		for f := seg[0] + 10; f < seg[1]; f += 20 {
+			if fr := RadarOf(f); fr > r {
+				r = fr
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,408 @@
+package scanner
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"wifiscanner/scanner/channel"
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+	register("demo", func() Backend { return &demoBackend{started: time.Now()} })
+}
+
+// demoRadarAfter is when the mock DFS network "detects radar" and vacates
+// its channel, so channel-move alerts can be seen in a demo run.
+const demoRadarAfter = 45 * time.Second
+
+// demoRebootAfter is when the mock WEP router crashes and restarts, so
+// reboot alerts can be seen too.
+const demoRebootAfter = 75 * time.Second
+
+// demoBackend simulates a neighbourhood of networks so the UI can run
+// without root or WiFi hardware.
+type demoBackend struct {
+	started time.Time
+}
+
+func (b *demoBackend) Name() string { return "demo" }
+
+func (b *demoBackend) Capabilities() Capabilities { return CapRawIEs }
+
+// Interfaces reports a built-in card and a USB dongle.
+func (b *demoBackend) Interfaces() ([]string, error) {
+	return []string{"wlan0", "wlan1"}, nil
+}
+
+// demoMock is one simulated access point.
+type demoMock struct {
+	ssid       string
+	bssid      string
+	security   string
+	baseSignal int
+	freq       int
+	gen        int // Wi-Fi generation, 0 for legacy
+}
+
+// demoMocks is the simulated neighbourhood.
+var demoMocks = []demoMock{
+	{"NETGEAR-5G-Home", "A4:2B:8C:D1:E5:F0", "WPA2", -35, 5180, 5},
+	{"xfinitywifi", "B0:C7:45:3A:91:DE", "OPEN", -42, 2437, 4},
+	{"FBI_Surveillance_Van_7", "C8:3A:35:FF:02:11", "WPA3", -48, 5240, 6},
+	{"Pretty Fly for a WiFi", "D4:01:C3:7E:A8:55", "WPA2", -55, 2412, 4},
+	{"The LAN Before Time", "10:68:3F:6B:33:C7", "WPA2/WPA3", -58, 2462, 6},
+	{"Bill Wi the Science Fi", "28:C6:8E:CE:47:9B", "WPA2/WPA", -63, 2427, 4},
+	{"DROP TABLE *;--", "00:0E:8E:BE:EF:00", "WPA2", -65, 5300, 5},
+	{"Skynet Global Defense", "00:09:0F:44:55:66", "WPA3-ENT-192", -68, 5500, 6},
+	{"404 Network Unavail", "AC:67:06:DD:EE:01", "WPA2-ENT", -72, 2452, 4},
+	{"wu-tang LAN", "34:A1:F7:8C:22:D0", "WPA2", -74, 2417, 4},
+	{"<hidden>", "B4:FB:E4:BC:DE:F0", "WPA2", -76, 5220, 5},
+	{"linksys", "78:A0:51:3E:C9:44", "WEP", -78, 2422, 0},
+	{"DIRECT-roku-123", "9C:B2:E4:16:F8:73", "WPA2", -82, 2447, 4},
+	{"HP-Print-A1-Officejet", "B0:5A:DA:01:23:45", "OPEN", -85, 2432, 4},
+	{"oldrouter", "D0:E1:F2:03:14:25", "OPEN", -88, 2442, 0},
+	{"TP-Link_Guest_5G", "50:C7:BF:15:26:37", "WPA2", -91, 5745, 5},
+	{"Cafe Free WiFi", "02:1A:11:F0:00:01", "OPEN", -66, 2412, 6},
+	{"<hidden>", "02:1A:11:F0:00:02", "OWE", -66, 2412, 6},
+	{"Starlink-6E", "74:24:9F:6E:00:21", "WPA3", -70, 6115, 6},
+	{"BE19000-Lab", "3C:52:A1:7B:E0:07", "WPA3", -79, 6275, 7},
+	{"FRITZ!Box 7590 XY", "3C:A6:2F:12:34:56", "WPA2", -80, 2472, 4},
+	{"NETGEAR-5G-Home", "A4:2B:8C:D1:E5:F8", "WPA2", -71, 5745, 5},
+}
+
+// mocks returns the mock table as it stands now. Skynet sits on DFS channel
+// 100 until it "detects radar" and moves to 149.
+func (b *demoBackend) mocks() []demoMock {
+	mocks := append([]demoMock(nil), demoMocks...)
+	if !b.started.IsZero() && time.Since(b.started) > demoRadarAfter {
+		for i := range mocks {
+			if mocks[i].bssid == "00:09:0F:44:55:66" {
+				mocks[i].freq = 5745
+			}
+		}
+	}
+	return mocks
+}
+
+// Scan generates realistic fake network data for demo/testing.
+func (b *demoBackend) Scan(iface string) ([]Network, error) {
+	mocks := b.mocks()
+
+	// The cafe runs Enhanced Open in transition mode: each half of the pair
+	// advertises the other
+	oweLinks := map[string]*OWETransition{
+		"02:1A:11:F0:00:01": {BSSID: "02:1A:11:F0:00:02", SSID: "Cafe Free WiFi-OWE"},
+		"02:1A:11:F0:00:02": {BSSID: "02:1A:11:F0:00:01", SSID: "Cafe Free WiFi"},
+	}
+
+	// Busier APs advertise BSS Load: base station counts, drifting per scan
+	loads := map[string]int{
+		"A4:2B:8C:D1:E5:F0": 4,
+		"B0:C7:45:3A:91:DE": 23,
+		"C8:3A:35:FF:02:11": 2,
+		"00:09:0F:44:55:66": 41,
+		"AC:67:06:DD:EE:01": 12,
+		"02:1A:11:F0:00:01": 17,
+		"02:1A:11:F0:00:02": 17,
+	}
+
+	// Consumer gear still advertising WPS, one of them locked after PIN
+	// guessing
+	wps := map[string]*WPSInfo{
+		"A4:2B:8C:D1:E5:F0": {Version: "2.0", Configured: true, DeviceName: "R7000", Manufacturer: "NETGEAR, Inc.",
+			Model: "R7000", ModelNumber: "R7000", SerialNumber: "4KR3947B00A1C", UUID: "4c2a9b1e-7f3d-4e21-9a6b-a42b8cd1e5f0"},
+		"78:A0:51:3E:C9:44": {Version: "1.0", Configured: true, DeviceName: "WRT54GL", Manufacturer: "Linksys",
+			Model: "WRT54GL", ModelNumber: "v1.1", SerialNumber: "CL7B1H912345", UUID: "00010203-0405-0607-0809-78a0513ec944"},
+		"10:68:3F:6B:33:C7": {Version: "2.0", Configured: true, Locked: true, DeviceName: "Archer AX55", Manufacturer: "TP-Link",
+			Model: "Archer AX55", ModelNumber: "1.0", SerialNumber: "1.0", UUID: "87654321-9abc-def0-1234-10683f6b33c7"},
+		"B0:5A:DA:01:23:45": {Version: "2.0", Configured: true, DeviceName: "HP OfficeJet Pro 9010", Manufacturer: "HP",
+			Model: "OfficeJet Pro 9010", ModelNumber: "1KR53D", SerialNumber: "TH0A1B2C3D"},
+	}
+
+	// The NETGEAR mesh satellite was set up without 802.11v, so clients
+	// cannot be steered between it and the router
+	roaming := map[string]RoamingInfo{
+		"A4:2B:8C:D1:E5:F0": {MobilityDomain: true, MDID: 0x4e47, FTOverDS: true, RRM: true, NeighborReport: true, BSSTransition: true},
+		"A4:2B:8C:D1:E5:F8": {MobilityDomain: true, MDID: 0x4e47, FTOverDS: true, RRM: true, NeighborReport: true},
+		"00:09:0F:44:55:66": {MobilityDomain: true, MDID: 0x0001, RRM: true, NeighborReport: true, BSSTransition: true},
+		"C8:3A:35:FF:02:11": {RRM: true, BSSTransition: true},
+		"74:24:9F:6E:00:21": {RRM: true, NeighborReport: true, BSSTransition: true},
+		"3C:52:A1:7B:E0:07": {RRM: true, NeighborReport: true, BSSTransition: true},
+	}
+
+	// Controller-managed APs name themselves in vendor elements
+	attributes := map[string][]Attribute{
+		"00:09:0F:44:55:66": {
+			{Source: "Extended Capabilities", Name: "BSS Transition"},
+			{Source: "Extended Capabilities", Name: "Multiple BSSID"},
+			{Source: "Extended Capabilities", Name: "TWT Responder Support"},
+			{Source: "WMM", Name: "Version", Value: "1"},
+			{Source: "WMM", Name: "U-APSD"},
+			{Source: "Cisco CCX1", Name: attrAPName, Value: "SKYNET-T800-01"},
+			{Source: "Cisco", Name: "CCX version", Value: "5"},
+		},
+		"AC:67:06:DD:EE:01": {
+			{Source: "Extended Capabilities", Name: "Interworking"},
+			{Source: "WMM", Name: "Version", Value: "1"},
+			{Source: "Aruba", Name: attrAPName, Value: "ap-3f-east-12"},
+		},
+		"A4:2B:8C:D1:E5:F0": {
+			{Source: "Extended Capabilities", Name: "BSS Transition"},
+			{Source: "WMM", Name: "Version", Value: "1"},
+			{Source: "WMM", Name: "U-APSD"},
+		},
+	}
+
+	networks := make([]Network, len(mocks))
+	now := time.Now()
+
+	for i, m := range mocks {
+		jitter := rand.Intn(7) - 3 // -3 to +3 dBm variation
+		networks[i] = Network{
+			BSSID:        m.bssid,
+			SSID:         m.ssid,
+			Signal:       m.baseSignal + jitter,
+			Frequency:    m.freq,
+			Channel:      channel.Number(m.freq),
+			SecurityInfo: demoSecurity(m.security),
+			PHY:          demoPHY(m.gen, m.freq),
+			LastSeen:     now,
+		}
+		networks[i].Security = networks[i].SecurityInfo.Class()
+		networks[i].OWETransition = oweLinks[m.bssid]
+		networks[i].WPS = wps[m.bssid]
+		networks[i].Roaming = roaming[m.bssid]
+		networks[i].Attributes = attributes[m.bssid]
+		networks[i].APName = apName(networks[i].Attributes)
+		networks[i].TSF = b.demoTSF(m.bssid)
+		switch {
+		case m.bssid == "3C:A6:2F:12:34:56":
+			networks[i].Country = demoCountry("DE")
+		case m.gen >= 4:
+			networks[i].Country = demoCountry("US")
+		}
+		if base, ok := loads[m.bssid]; ok {
+			sta := base + rand.Intn(5) - 2
+			if sta < 0 {
+				sta = 0
+			}
+			networks[i].BSSLoad = &BSSLoad{
+				StationCount:      sta,
+				Utilisation:       min(255, 15+sta*5+rand.Intn(20)),
+				AdmissionCapacity: 31250 - sta*600,
+			}
+		}
+	}
+
+	// Occasional roaming network to exercise new-network alerts (~30% chance)
+	if rand.Intn(10) < 3 {
+		networks = append(networks, Network{
+			BSSID:        "A4:77:33:AB:CD:EF",
+			SSID:         "GoogleGuest-5G",
+			Signal:       -60 + rand.Intn(7) - 3,
+			Frequency:    5500,
+			Channel:      channel.Number(5500),
+			Security:     "WPA2",
+			LastSeen:     now,
+			SecurityInfo: demoSecurity("WPA2"),
+			PHY:          demoPHY(5, 5500),
+		})
+	}
+
+	for i := range networks {
+		networks[i].IEs = demoIEs(networks[i])
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Signal > networks[j].Signal
+	})
+
+	return networks, nil
+}
+
+// Survey reports a noise floor around -95 dBm on every channel the mock
+// networks use, busier the more networks share it. It counts the mock
+// table rather than scanning, so it matches the networks on screen.
+func (b *demoBackend) Survey(iface string) ([]ChannelSurvey, error) {
+	perFreq := make(map[int]int)
+	for f := 2412; f <= 2462; f += 5 {
+		perFreq[f] = 0
+	}
+	for _, f := range []int{5180, 5200, 5220, 5240, 5260, 5280, 5300, 5320, 5500, 5745} {
+		perFreq[f] = 0
+	}
+	for _, m := range b.mocks() {
+		perFreq[m.freq]++
+	}
+
+	var surveys []ChannelSurvey
+	for freq, count := range perFreq {
+		active := 100 * time.Millisecond
+		busy := time.Duration(5+count*12+rand.Intn(8)) * time.Millisecond
+		if busy > active {
+			busy = active
+		}
+		surveys = append(surveys, ChannelSurvey{
+			Interface: iface,
+			Frequency: freq,
+			Noise:     -95 + rand.Intn(5) - 2,
+			Active:    active,
+			Busy:      busy,
+			Receive:   busy * 4 / 5,
+			Transmit:  busy / 20,
+		})
+	}
+	sortSurveys(surveys)
+	return surveys, nil
+}
+
+// demoCountry returns the Country element an AP configured for code
+// typically sends. The imported FRITZ!Box in the mock table keeps its
+// German settings, channel 13 included.
+func demoCountry(code string) *CountryInfo {
+	c := &CountryInfo{Code: code, Environment: "Indoor/Outdoor"}
+	switch code {
+	case "DE":
+		c.Subbands = []Subband{{1, 13, 20}, {36, 4, 23}, {52, 4, 20}, {100, 11, 27}}
+	default:
+		c.Subbands = []Subband{{1, 11, 30}, {36, 4, 24}, {52, 4, 24}, {100, 12, 24}, {149, 5, 30}}
+	}
+	return c
+}
+
+// demoTSF gives each mock AP an uptime of up to a few weeks, derived from
+// its BSSID, that advances with the demo. The linksys router reboots.
+func (b *demoBackend) demoTSF(bssid string) uint64 {
+	running := time.Since(b.started)
+	if bssid == "78:A0:51:3E:C9:44" && running > demoRebootAfter {
+		return uint64((running - demoRebootAfter) / time.Microsecond)
+	}
+	last, _ := strconv.ParseUint(bssid[len(bssid)-2:], 16, 8)
+	return uint64((time.Duration(last)*3*time.Hour + running) / time.Microsecond)
+}
+
+// demoIEs encodes the basic elements of a mock network so the raw element
+// view has something to show.
+func demoIEs(n Network) []byte {
+	add := func(buf []byte, id byte, data ...byte) []byte {
+		return append(append(buf, id, byte(len(data))), data...)
+	}
+
+	var ies []byte
+	if n.SSID != "<hidden>" {
+		ies = add(ies, ieSSID, []byte(n.SSID)...)
+	} else {
+		ies = add(ies, ieSSID)
+	}
+	ies = add(ies, 1, 0x82, 0x84, 0x8B, 0x96, 0x0C, 0x12, 0x18, 0x24) // Supported Rates
+	ies = add(ies, ieDSParams, byte(n.Channel))
+	if c := n.Country; c != nil {
+		data := []byte(c.Code + " ")
+		for _, s := range c.Subbands {
+			data = append(data, byte(s.FirstChannel), byte(s.NumChannels), byte(s.MaxPower))
+		}
+		ies = add(ies, ieCountry, data...)
+	}
+	if l := n.BSSLoad; l != nil {
+		ies = add(ies, ieBSSLoad, byte(l.StationCount), byte(l.StationCount>>8), byte(l.Utilisation),
+			byte(l.AdmissionCapacity), byte(l.AdmissionCapacity>>8))
+	}
+	return ies
+}
+
+// Regulatory reports the US domain.
+func (b *demoBackend) Regulatory() (*RegDomain, error) {
+	return &RegDomain{
+		Country:   "US",
+		DFSRegion: "DFS-FCC",
+		Rules: []RegRule{
+			{StartFreq: 2400, EndFreq: 2472, MaxBandwidth: 40, MaxEIRP: 30},
+			{StartFreq: 5150, EndFreq: 5250, MaxBandwidth: 80, MaxEIRP: 23, Flags: []string{"AUTO-BW"}},
+			{StartFreq: 5250, EndFreq: 5350, MaxBandwidth: 80, MaxEIRP: 24, Flags: []string{"DFS", "AUTO-BW"}},
+			{StartFreq: 5470, EndFreq: 5730, MaxBandwidth: 160, MaxEIRP: 24, Flags: []string{"DFS"}},
+			{StartFreq: 5730, EndFreq: 5850, MaxBandwidth: 80, MaxEIRP: 30, Flags: []string{"AUTO-BW"}},
+			{StartFreq: 5925, EndFreq: 7125, MaxBandwidth: 320, MaxEIRP: 12, Flags: []string{"NO-OUTDOOR"}},
+		},
+	}, nil
+}
+
+// demoSecurity returns a typical SecurityInfo for a mock security profile.
+func demoSecurity(profile string) SecurityInfo {
+	wpa2 := SecurityInfo{
+		RSN:             true,
+		GroupCipher:     "CCMP",
+		PairwiseCiphers: []string{"CCMP"},
+		AKMs:            []string{"PSK"},
+		HasCapabilities: true,
+	}
+
+	switch profile {
+	case "WPA2":
+		return wpa2
+	case "WPA2/WPA":
+		wpa2.WPA = true
+		wpa2.GroupCipher = "TKIP"
+		wpa2.PairwiseCiphers = []string{"CCMP", "TKIP"}
+		return wpa2
+	case "WPA2/WPA3":
+		wpa2.AKMs = []string{"PSK", "SAE"}
+		wpa2.PMFCapable = true
+		wpa2.RSNCapabilities = rsnCapMFPCapable
+		return wpa2
+	case "WPA3":
+		wpa2.AKMs = []string{"SAE"}
+		wpa2.PMFCapable, wpa2.PMFRequired = true, true
+		wpa2.RSNCapabilities = rsnCapMFPCapable | rsnCapMFPRequired
+		return wpa2
+	case "WPA2-ENT":
+		wpa2.AKMs = []string{"802.1X", "FT-802.1X"}
+		return wpa2
+	case "WPA3-ENT-192":
+		wpa2.GroupCipher = "GCMP-256"
+		wpa2.PairwiseCiphers = []string{"GCMP-256"}
+		wpa2.AKMs = []string{"SuiteB-192"}
+		wpa2.PMFCapable, wpa2.PMFRequired = true, true
+		wpa2.RSNCapabilities = rsnCapMFPCapable | rsnCapMFPRequired
+		return wpa2
+	case "OWE":
+		wpa2.AKMs = []string{"OWE"}
+		wpa2.PMFCapable, wpa2.PMFRequired = true, true
+		wpa2.RSNCapabilities = rsnCapMFPCapable | rsnCapMFPRequired
+		return wpa2
+	case "WEP":
+		return SecurityInfo{Privacy: true}
+	default:
+		return SecurityInfo{}
+	}
+}
+
+// demoPHY returns typical capabilities for an AP of the given Wi-Fi
+// generation on freq.
+func demoPHY(gen, freq int) PHYInfo {
+	p := PHYInfo{ChannelWidth: 20}
+	if gen < 4 {
+		return p
+	}
+	p.HT, p.ShortGI, p.HTMCS, p.SpatialStreams = true, true, 15, 2
+	if freq > 5000 {
+		p.ChannelWidth, p.SecondaryOffset = 40, 1
+	}
+	if gen >= 5 && freq > 5000 {
+		p.VHT, p.VHTMCS, p.ChannelWidth = true, 9, 80
+	}
+	if gen >= 6 {
+		p.HE, p.HEMCS = true, 11
+		if freq >= 5925 {
+			// 6 GHz carries no HT/VHT elements
+			p.HT, p.VHT, p.HTMCS, p.VHTMCS = false, false, 0, 0
+			p.ChannelWidth, p.SpatialStreams = 160, 4
+		}
+	}
+	if gen >= 7 {
+		p.EHT, p.EHTMCS, p.ChannelWidth = true, 13, 320
+	}
+	return p
+}
@@ -0,0 +1,111 @@
+package scanner
+
+import "fmt"
+
+// RawElement is one information element exactly as the AP sent it.
+type RawElement struct {
+	ID   int
+	Data []byte
+}
+
+// Elements splits the raw information elements of n. It is empty unless
+// the backend has CapRawIEs.
+func (n Network) Elements() []RawElement {
+	elems := parseIEs(n.IEs)
+	raw := make([]RawElement, len(elems))
+	for i, e := range elems {
+		raw[i] = RawElement{ID: int(e.ID), Data: e.Data}
+	}
+	return raw
+}
+
+// elementNames names the element IDs seen in beacons and probe responses.
+var elementNames = map[byte]string{
+	0:   "SSID",
+	1:   "Supported Rates",
+	3:   "DS Parameter Set",
+	5:   "TIM",
+	7:   "Country",
+	11:  "BSS Load",
+	32:  "Power Constraint",
+	35:  "TPC Report",
+	37:  "Channel Switch Announcement",
+	42:  "ERP Information",
+	45:  "HT Capabilities",
+	46:  "QoS Capability",
+	48:  "RSN",
+	50:  "Extended Supported Rates",
+	54:  "Mobility Domain",
+	59:  "Supported Operating Classes",
+	61:  "HT Operation",
+	62:  "Secondary Channel Offset",
+	70:  "RM Enabled Capabilities",
+	71:  "Multiple BSSID",
+	72:  "20/40 BSS Coexistence",
+	74:  "Overlapping BSS Scan Parameters",
+	107: "Interworking",
+	108: "Advertisement Protocol",
+	111: "Roaming Consortium",
+	113: "Mesh Configuration",
+	114: "Mesh ID",
+	127: "Extended Capabilities",
+	133: "Cisco CCX1 CKIP + Device Name",
+	191: "VHT Capabilities",
+	192: "VHT Operation",
+	195: "Transmit Power Envelope",
+	199: "Operating Mode Notification",
+	201: "Reduced Neighbor Report",
+	221: "Vendor Specific",
+	244: "RSN Extension",
+	255: "Element ID Extension",
+}
+
+// extensionNames names the Element ID Extension values (element 255).
+var extensionNames = map[byte]string{
+	35:  "HE Capabilities",
+	36:  "HE Operation",
+	37:  "UORA Parameter Set",
+	38:  "MU EDCA Parameter Set",
+	39:  "Spatial Reuse Parameter Set",
+	55:  "BSS Color Change Announcement",
+	59:  "HE 6 GHz Band Capabilities",
+	106: "EHT Operation",
+	107: "Multi-Link",
+	108: "EHT Capabilities",
+}
+
+// vendorTypeNames names vendor elements by OUI and vendor type.
+var vendorTypeNames = map[uint32]map[byte]string{
+	ouiMicrosoft: {msTypeWPA: "WPA", msTypeWMM: "WMM", msTypeWPS: "WPS"},
+	ouiWFA:       {0x09: "P2P", 0x10: "Hotspot 2.0 Indication", wfaTypeOWETrans: "OWE Transition Mode"},
+}
+
+// Name describes the element, e.g. "HE Operation" or "Vendor Specific
+// (WPS)".
+func (e RawElement) Name() string {
+	raw := element{ID: byte(e.ID), Data: e.Data}
+	switch {
+	case e.ID == ieExtension && len(e.Data) > 0:
+		if name, ok := extensionNames[e.Data[0]]; ok {
+			return name
+		}
+		return fmt.Sprintf("Extension %d", e.Data[0])
+	case e.ID == ieVendor:
+		oui, typ, ok := raw.vendorOUI()
+		if !ok {
+			break
+		}
+		if names, ok := vendorTypeNames[oui]; ok {
+			if name, ok := names[typ]; ok {
+				return "Vendor Specific (" + name + ")"
+			}
+		} else if d, ok := vendorDecoders[oui]; ok {
+			return "Vendor Specific (" + d.source + ")"
+		}
+		return fmt.Sprintf("Vendor Specific (%02X:%02X:%02X)", e.Data[0], e.Data[1], e.Data[2])
+	}
+	if name, ok := elementNames[byte(e.ID)]; ok {
+		return name
+	}
+	return "Unknown"
+}
@@ -0,0 +1,156 @@
+package scanner
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 802.11 information element IDs.
+const (
+	ieSSID     = 0
+	ieDSParams = 3
+	ieRSN      = 48
+	ieVendor   = 221
+)
+
+// capPrivacy is the Privacy bit of the beacon capability field.
+const capPrivacy = 0x0010
+
+// msTypeWPA is the Microsoft vendor element type carrying legacy WPA.
+const msTypeWPA = 1
+
+// element is one raw information element.
+type element struct {
+	ID   byte
+	Data []byte
+}
+
+// parseIEs splits a raw information element buffer into elements. A
+// truncated trailing element is dropped.
+func parseIEs(buf []byte) []element {
+	var elems []element
+	for len(buf) >= 2 {
+		id, l := buf[0], int(buf[1])
+		if len(buf) < 2+l {
+			break
+		}
+		elems = append(elems, element{ID: id, Data: buf[2 : 2+l]})
+		buf = buf[2+l:]
+	}
+	return elems
+}
+
+// vendorOUI returns the OUI and vendor type of a vendor-specific element.
+func (e element) vendorOUI() (oui uint32, typ byte, ok bool) {
+	if e.ID != ieVendor || len(e.Data) < 4 {
+		return 0, 0, false
+	}
+	return uint32(e.Data[0])<<16 | uint32(e.Data[1])<<8 | uint32(e.Data[2]), e.Data[3], true
+}
+
+// iwVendorRe matches the three ways 'iw scan -u' dumps vendor elements it
+// has no printer for: "Vendor specific: OUI 00:40:96, data: ...",
+// "WFA 0x1c, data: ..." and "MS/WiFi 0x04, data: ...".
+var iwVendorRe = regexp.MustCompile(`(?m)^\s*(?:Vendor specific: OUI ([0-9a-fA-F:]{8})|(WFA|MS/WiFi) 0x([0-9a-fA-F]+)), data:([0-9a-fA-F ]*)$`)
+
+// iwVendorElements rebuilds raw vendor elements from iw's hex dumps.
+func iwVendorElements(block string) []element {
+	var elems []element
+	for _, m := range iwVendorRe.FindAllStringSubmatch(block, -1) {
+		var prefix []byte
+		switch {
+		case m[1] != "":
+			prefix, _ = hex.DecodeString(strings.ReplaceAll(m[1], ":", ""))
+		case m[2] == "WFA":
+			typ, _ := strconv.ParseUint(m[3], 16, 8)
+			prefix = []byte{0x50, 0x6F, 0x9A, byte(typ)}
+		default:
+			typ, _ := strconv.ParseUint(m[3], 16, 8)
+			prefix = []byte{0x00, 0x50, 0xF2, byte(typ)}
+		}
+		data, err := hex.DecodeString(strings.ReplaceAll(m[4], " ", ""))
+		if err != nil {
+			continue
+		}
+		elems = append(elems, element{ID: ieVendor, Data: append(prefix, data...)})
+	}
+	return elems
+}
+
+// applyIEs fills the Network fields that can be derived from raw
+// information elements and the beacon capability field.
+func applyIEs(n *Network, ies []byte, capability uint16) {
+	si := SecurityInfo{Privacy: capability&capPrivacy != 0}
+	phy := PHYInfo{ChannelWidth: 20}
+	var roam RoamingInfo
+	var wps []byte // WPS attributes may be split over several elements
+
+	elems := parseIEs(ies)
+	for _, e := range elems {
+		switch e.ID {
+		case ieSSID:
+			n.SSID = escapeSSID(e.Data)
+		case ieDSParams:
+			if len(e.Data) >= 1 {
+				n.Channel = int(e.Data[0])
+			}
+		case ieRSN:
+			si.decodeRSN(e.Data)
+		case ieBSSLoad:
+			n.BSSLoad = decodeBSSLoad(e.Data)
+		case ieCountry:
+			n.Country = decodeCountry(e.Data)
+		case ieMobilityDomain:
+			roam.decodeMobilityDomain(e.Data)
+		case ieRRMEnabled:
+			roam.decodeRRM(e.Data)
+		case ieExtCapabilities:
+			roam.BSSTransition = extCapBit(e.Data, extCapBSSTransition)
+		case ieVendor:
+			oui, typ, ok := e.vendorOUI()
+			switch {
+			case !ok:
+			case oui == ouiMicrosoft && typ == msTypeWPA:
+				si.decodeWPA(e.Data[4:])
+			case oui == ouiWFA && typ == wfaTypeOWETrans:
+				n.OWETransition = decodeOWETransition(e.Data[4:])
+			case oui == ouiMicrosoft && typ == msTypeWPS:
+				wps = append(wps, e.Data[4:]...)
+			}
+		default:
+			phy.decodeElement(e)
+		}
+	}
+
+	if wps != nil {
+		n.WPS = decodeWPS(wps)
+	}
+	roam.FTAKM = hasFTAKM(si)
+	n.SecurityInfo = si
+	n.PHY = phy
+	n.Roaming = roam
+	n.Attributes = decodeAttributes(elems)
+	n.APName = apName(n.Attributes)
+	n.IEs = append([]byte(nil), ies...)
+	n.Security = si.Class()
+}
+
+// escapeSSID renders raw SSID bytes the way iw prints them, so both backends
+// report identical names.
+func escapeSSID(ssid []byte) string {
+	var b strings.Builder
+	for i, c := range ssid {
+		switch {
+		case c > ' ' && c < 0x7f && c != '\\':
+			b.WriteByte(c)
+		case c == ' ' && i != 0 && i != len(ssid)-1:
+			b.WriteByte(' ')
+		default:
+			fmt.Fprintf(&b, "\\x%.2x", c)
+		}
+	}
+	return b.String()
+}
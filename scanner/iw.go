@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+func init() {
+	register("iw", func() Backend { return &iwBackend{} })
+}
+
+// iwBackend scans by shelling out to iw and parsing its text output.
+type iwBackend struct{}
+
+func (b *iwBackend) Name() string { return "iw" }
+
+func (b *iwBackend) Capabilities() Capabilities {
+	return CapNeedsRoot | CapActiveScan
+}
+
+// Scan runs 'iw dev <iface> scan' and parses the result.
+func (b *iwBackend) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+// ScanRaw is Scan that also returns iw's text output. -u makes iw dump the
+// elements it cannot decode, such as most vendor elements, as hex.
+func (b *iwBackend) ScanRaw(iface string) ([]byte, []Network, error) {
+	// Try active scan first, fall back to cached results
+	out, err := exec.Command("iw", "dev", iface, "scan", "-u").CombinedOutput()
+	if err != nil {
+		out, err = exec.Command("iw", "dev", iface, "scan", "dump", "-u").CombinedOutput()
+		if err != nil {
+			return nil, nil, fmt.Errorf("scan failed: %w\n%s", err, string(out))
+		}
+	}
+
+	return out, parseScanOutput(string(out)), nil
+}
+
+// Interfaces lists wireless interfaces via 'iw dev'.
+func (b *iwBackend) Interfaces() ([]string, error) {
+	out, err := exec.Command("iw", "dev").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'iw dev': %w", err)
+	}
+
+	re := regexp.MustCompile(`Interface\s+(\S+)`)
+	var ifaces []string
+	for _, m := range re.FindAllStringSubmatch(string(out), -1) {
+		ifaces = append(ifaces, m[1])
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no wireless interface detected in 'iw dev' output")
+	}
+	return ifaces, nil
+}
+
+// Survey reads the per-channel survey data the driver gathered while
+// scanning.
+func (b *iwBackend) Survey(iface string) ([]ChannelSurvey, error) {
+	out, err := exec.Command("iw", "dev", iface, "survey", "dump").Output()
+	if err != nil {
+		return nil, fmt.Errorf("survey dump failed: %w", err)
+	}
+	return parseSurveyDump(iface, string(out)), nil
+}
+
+// Regulatory reads the global regulatory domain from `iw reg get`.
+func (b *iwBackend) Regulatory() (*RegDomain, error) {
+	out, err := exec.Command("iw", "reg", "get").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'iw reg get': %w", err)
+	}
+	return parseRegGet(string(out))
+}
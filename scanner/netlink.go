@@ -0,0 +1,331 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"wifiscanner/scanner/channel"
+)
+
+// Netlink message types, flags and attribute bits (linux/netlink.h).
+const (
+	nlmsgHdrLen   = 16
+	genlHdrLen    = 4
+	nlaHdrLen     = 4
+	nlmsgError    = 2
+	nlmsgDone     = 3
+	nlmFRequest   = 0x1
+	nlmFMulti     = 0x2
+	nlmFAck       = 0x4
+	nlmFDump      = 0x300
+	nlaTypeMask   = 0x3fff
+	nlaFNested    = 0x8000
+	genlIDCtrl    = 0x10
+	ctrlCmdGetFam = 3
+)
+
+// Generic netlink controller attributes (linux/genetlink.h).
+const (
+	ctrlAttrFamilyID    = 1
+	ctrlAttrFamilyName  = 2
+	ctrlAttrMcastGroups = 7
+	ctrlAttrMcastName   = 1
+	ctrlAttrMcastID     = 2
+)
+
+// nl80211 commands and attributes used by the scan backend (linux/nl80211.h).
+const (
+	nl80211CmdGetInterface   = 5
+	nl80211CmdNewInterface   = 7
+	nl80211CmdGetScan        = 32
+	nl80211CmdTriggerScan    = 33
+	nl80211CmdNewScanResults = 34
+	nl80211CmdScanAborted    = 35
+	nl80211CmdGetSurvey      = 50
+	nl80211CmdNewSurvey      = 51
+
+	nl80211AttrIfindex   = 3
+	nl80211AttrIfname    = 4
+	nl80211AttrScanSSIDs = 45
+	nl80211AttrBSS       = 47
+	nl80211AttrSurvey    = 84
+
+	nl80211BSSBSSID        = 1
+	nl80211BSSFrequency    = 2
+	nl80211BSSTSF          = 3
+	nl80211BSSCapabilty    = 5
+	nl80211BSSIEs          = 6
+	nl80211BSSSignalMBM    = 7
+	nl80211BSSSignalUnspec = 8
+	nl80211BSSBeaconIEs    = 11
+
+	nl80211SurveyFrequency = 1
+	nl80211SurveyNoise     = 2
+	nl80211SurveyInUse     = 3
+	nl80211SurveyTime      = 4
+	nl80211SurveyTimeBusy  = 5
+	nl80211SurveyTimeRx    = 7
+	nl80211SurveyTimeTx    = 8
+)
+
+// nlEndian is the byte order of netlink headers and attributes, which is
+// always the host's.
+var nlEndian = hostEndian()
+
+func hostEndian() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// nlMessage is one decoded netlink message.
+type nlMessage struct {
+	Type    uint16
+	Flags   uint16
+	Seq     uint32
+	Payload []byte
+}
+
+// parseNetlinkMessages splits a buffer of back-to-back netlink messages.
+func parseNetlinkMessages(buf []byte) ([]nlMessage, error) {
+	var msgs []nlMessage
+	for len(buf) >= nlmsgHdrLen {
+		l := int(nlEndian.Uint32(buf[0:4]))
+		if l < nlmsgHdrLen || l > len(buf) {
+			return msgs, fmt.Errorf("netlink: bad message length %d", l)
+		}
+		msgs = append(msgs, nlMessage{
+			Type:    nlEndian.Uint16(buf[4:6]),
+			Flags:   nlEndian.Uint16(buf[6:8]),
+			Seq:     nlEndian.Uint32(buf[8:12]),
+			Payload: buf[nlmsgHdrLen:l],
+		})
+		buf = buf[nlAlign(l):]
+	}
+	return msgs, nil
+}
+
+// errno returns the error carried by an NLMSG_ERROR message (nil for an ACK).
+func (m nlMessage) errno() error {
+	if len(m.Payload) < 4 {
+		return fmt.Errorf("netlink: truncated error message")
+	}
+	code := int32(nlEndian.Uint32(m.Payload[0:4]))
+	if code == 0 {
+		return nil
+	}
+	return syscall.Errno(-code)
+}
+
+// genlAttrs returns the command and top-level attributes of a generic
+// netlink message.
+func (m nlMessage) genlAttrs() (cmd uint8, attrs map[uint16][]byte) {
+	if len(m.Payload) < genlHdrLen {
+		return 0, nil
+	}
+	return m.Payload[0], parseAttrs(m.Payload[genlHdrLen:])
+}
+
+// parseAttrs decodes a run of netlink attributes into a type -> payload map.
+func parseAttrs(buf []byte) map[uint16][]byte {
+	attrs := make(map[uint16][]byte)
+	for len(buf) >= nlaHdrLen {
+		l := int(nlEndian.Uint16(buf[0:2]))
+		if l < nlaHdrLen || l > len(buf) {
+			break
+		}
+		attrs[nlEndian.Uint16(buf[2:4])&nlaTypeMask] = buf[nlaHdrLen:l]
+		buf = buf[min(nlAlign(l), len(buf)):]
+	}
+	return attrs
+}
+
+// parseAttrList decodes nested attributes whose types are list indices,
+// returning the payloads in order.
+func parseAttrList(buf []byte) [][]byte {
+	var list [][]byte
+	for len(buf) >= nlaHdrLen {
+		l := int(nlEndian.Uint16(buf[0:2]))
+		if l < nlaHdrLen || l > len(buf) {
+			break
+		}
+		list = append(list, buf[nlaHdrLen:l])
+		buf = buf[min(nlAlign(l), len(buf)):]
+	}
+	return list
+}
+
+// appendAttr encodes a netlink attribute onto buf.
+func appendAttr(buf []byte, typ uint16, data []byte) []byte {
+	hdr := make([]byte, nlaHdrLen)
+	nlEndian.PutUint16(hdr[0:2], uint16(nlaHdrLen+len(data)))
+	nlEndian.PutUint16(hdr[2:4], typ)
+	buf = append(buf, hdr...)
+	buf = append(buf, data...)
+	return append(buf, make([]byte, nlAlign(len(data))-len(data))...)
+}
+
+// appendMessage re-encodes m onto buf so a dump can be stored and decoded
+// later by parseNetlinkScanDump.
+func appendMessage(buf []byte, m nlMessage) []byte {
+	hdr := make([]byte, nlmsgHdrLen)
+	nlEndian.PutUint32(hdr[0:4], uint32(nlmsgHdrLen+len(m.Payload)))
+	nlEndian.PutUint16(hdr[4:6], m.Type)
+	nlEndian.PutUint16(hdr[6:8], m.Flags)
+	nlEndian.PutUint32(hdr[8:12], m.Seq)
+	buf = append(buf, hdr...)
+	buf = append(buf, m.Payload...)
+	return append(buf, make([]byte, nlAlign(len(m.Payload))-len(m.Payload))...)
+}
+
+func nlAlign(l int) int {
+	return (l + 3) &^ 3
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// parseNetlinkScanDump decodes the raw reply to an NL80211_CMD_GET_SCAN dump
+// (every message up to and including NLMSG_DONE) into networks.
+func parseNetlinkScanDump(buf []byte) ([]Network, error) {
+	msgs, err := parseNetlinkMessages(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var networks []Network
+	now := time.Now()
+
+	for _, m := range msgs {
+		switch m.Type {
+		case nlmsgDone:
+			continue
+		case nlmsgError:
+			if err := m.errno(); err != nil {
+				return nil, fmt.Errorf("scan dump failed: %w", err)
+			}
+			continue
+		}
+
+		cmd, attrs := m.genlAttrs()
+		if cmd != nl80211CmdNewScanResults || attrs[nl80211AttrBSS] == nil {
+			continue
+		}
+		if n, ok := parseBSS(attrs[nl80211AttrBSS]); ok {
+			n.LastSeen = now
+			networks = append(networks, n)
+		}
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Signal > networks[j].Signal
+	})
+
+	return networks, nil
+}
+
+// parseBSS decodes a nested NL80211_ATTR_BSS attribute.
+func parseBSS(buf []byte) (Network, bool) {
+	bss := parseAttrs(buf)
+
+	mac := bss[nl80211BSSBSSID]
+	if len(mac) != 6 {
+		return Network{}, false
+	}
+
+	var n Network
+	parts := make([]string, 6)
+	for i, b := range mac {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	n.BSSID = strings.Join(parts, ":")
+
+	if v := bss[nl80211BSSFrequency]; len(v) >= 4 {
+		n.Frequency = int(nlEndian.Uint32(v))
+	}
+	if v := bss[nl80211BSSTSF]; len(v) >= 8 {
+		n.TSF = nlEndian.Uint64(v)
+	}
+	if v := bss[nl80211BSSSignalMBM]; len(v) >= 4 {
+		n.Signal = int(int32(nlEndian.Uint32(v))) / 100
+	} else if v := bss[nl80211BSSSignalUnspec]; len(v) >= 1 {
+		// Drivers without dBm readings report a 0-100 quality
+		n.Signal = percentToDBm(int(v[0]))
+	}
+
+	var capability uint16
+	if v := bss[nl80211BSSCapabilty]; len(v) >= 2 {
+		capability = nlEndian.Uint16(v)
+	}
+
+	// Probe response IEs are the most complete; fall back to beacon IEs
+	ies := bss[nl80211BSSIEs]
+	if ies == nil {
+		ies = bss[nl80211BSSBeaconIEs]
+	}
+	applyIEs(&n, ies, capability)
+
+	if n.SSID == "" {
+		n.SSID = "<hidden>"
+	}
+	if n.Channel == 0 {
+		n.Channel = channel.Number(n.Frequency)
+	}
+
+	return n, true
+}
+
+// parseNetlinkSurveyDump decodes the raw reply to an NL80211_CMD_GET_SURVEY
+// dump into per-channel surveys.
+func parseNetlinkSurveyDump(iface string, buf []byte) ([]ChannelSurvey, error) {
+	msgs, err := parseNetlinkMessages(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var surveys []ChannelSurvey
+	for _, m := range msgs {
+		if m.Type == nlmsgError {
+			if err := m.errno(); err != nil {
+				return nil, fmt.Errorf("survey dump failed: %w", err)
+			}
+			continue
+		}
+		cmd, attrs := m.genlAttrs()
+		if cmd != nl80211CmdNewSurvey || attrs[nl80211AttrSurvey] == nil {
+			continue
+		}
+
+		info := parseAttrs(attrs[nl80211AttrSurvey])
+		freq := info[nl80211SurveyFrequency]
+		if len(freq) < 4 {
+			continue
+		}
+		c := ChannelSurvey{Interface: iface, Frequency: int(nlEndian.Uint32(freq))}
+		_, c.InUse = info[nl80211SurveyInUse]
+		if v := info[nl80211SurveyNoise]; len(v) >= 1 {
+			c.Noise = int(int8(v[0]))
+		}
+		ms := func(typ uint16) time.Duration {
+			if v := info[typ]; len(v) >= 8 {
+				return time.Duration(nlEndian.Uint64(v)) * time.Millisecond
+			}
+			return 0
+		}
+		c.Active, c.Busy = ms(nl80211SurveyTime), ms(nl80211SurveyTimeBusy)
+		c.Receive, c.Transmit = ms(nl80211SurveyTimeRx), ms(nl80211SurveyTimeTx)
+		surveys = append(surveys, c)
+	}
+	return surveys, nil
+}
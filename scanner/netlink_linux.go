@@ -0,0 +1,354 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const nlRecvBufSize = 1 << 16
+
+func init() {
+	register("nl80211", func() Backend { return &netlinkBackend{} })
+}
+
+// netlinkBackend scans by talking nl80211 over generic netlink directly,
+// avoiding the iw binary and its unstable text output.
+type netlinkBackend struct{}
+
+func (b *netlinkBackend) Name() string { return "nl80211" }
+
+func (b *netlinkBackend) Capabilities() Capabilities {
+	return CapNeedsRoot | CapActiveScan | CapRawIEs
+}
+
+// Scan triggers a scan on iface, waits for the kernel to report results and
+// dumps the BSS table. If a scan is already running the cached results are
+// returned instead.
+func (b *netlinkBackend) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+// ScanRaw is Scan that also returns the raw GET_SCAN dump messages.
+func (b *netlinkBackend) ScanRaw(iface string) ([]byte, []Network, error) {
+	raw, err := b.scanDump(iface)
+	if err != nil {
+		return nil, nil, err
+	}
+	networks, err := parseNetlinkScanDump(raw)
+	return raw, networks, err
+}
+
+// scanDump performs the scan and returns the raw GET_SCAN dump messages.
+func (b *netlinkBackend) scanDump(iface string) ([]byte, error) {
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: %w", err)
+	}
+
+	c, err := dialGenetlink()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	family, groups, err := c.resolveFamily("nl80211")
+	if err != nil {
+		return nil, err
+	}
+
+	ifindex := make([]byte, 4)
+	nlEndian.PutUint32(ifindex, uint32(ifi.Index))
+	ifAttr := appendAttr(nil, nl80211AttrIfindex, ifindex)
+
+	if group, ok := groups["scan"]; ok {
+		if err := c.join(group); err != nil {
+			return nil, err
+		}
+		// A single wildcard SSID makes this an active scan, as iw does
+		ssids := appendAttr(nil, 1, nil)
+		trigger := appendAttr(append([]byte(nil), ifAttr...), nl80211AttrScanSSIDs|nlaFNested, ssids)
+		err := c.request(family, nl80211CmdTriggerScan, nlmFAck, trigger)
+		switch {
+		case err == nil:
+			if err := c.waitScan(uint32(ifi.Index)); err != nil {
+				return nil, err
+			}
+		case errors.Is(err, unix.EBUSY):
+			// Another scan is running; its results land in the cache
+		default:
+			return nil, fmt.Errorf("trigger scan: %w", err)
+		}
+	}
+
+	return c.dump(family, nl80211CmdGetScan, ifAttr)
+}
+
+// Survey dumps the driver's per-channel survey data.
+func (b *netlinkBackend) Survey(iface string) ([]ChannelSurvey, error) {
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, fmt.Errorf("survey failed: %w", err)
+	}
+
+	c, err := dialGenetlink()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	family, _, err := c.resolveFamily("nl80211")
+	if err != nil {
+		return nil, err
+	}
+
+	ifindex := make([]byte, 4)
+	nlEndian.PutUint32(ifindex, uint32(ifi.Index))
+	raw, err := c.dump(family, nl80211CmdGetSurvey, appendAttr(nil, nl80211AttrIfindex, ifindex))
+	if err != nil {
+		return nil, err
+	}
+	return parseNetlinkSurveyDump(iface, raw)
+}
+
+// Interfaces lists wireless interfaces via NL80211_CMD_GET_INTERFACE.
+func (b *netlinkBackend) Interfaces() ([]string, error) {
+	c, err := dialGenetlink()
+	if err != nil {
+		return nil, err
+	}
+	defer c.close()
+
+	family, _, err := c.resolveFamily("nl80211")
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.dump(family, nl80211CmdGetInterface, nil)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := parseNetlinkMessages(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var ifaces []string
+	for _, m := range msgs {
+		cmd, attrs := m.genlAttrs()
+		if cmd != nl80211CmdNewInterface {
+			continue
+		}
+		if name := attrs[nl80211AttrIfname]; len(name) > 1 {
+			ifaces = append(ifaces, string(name[:len(name)-1])) // NUL-terminated
+		}
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no wireless interface reported by nl80211")
+	}
+	return ifaces, nil
+}
+
+// nlConn is a generic netlink socket.
+type nlConn struct {
+	fd  int
+	seq uint32
+}
+
+func dialGenetlink() (*nlConn, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_GENERIC)
+	if err != nil {
+		return nil, fmt.Errorf("netlink socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("netlink bind: %w", err)
+	}
+	tv := unix.NsecToTimeval(scanTimeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("netlink timeout: %w", err)
+	}
+	return &nlConn{fd: fd, seq: uint32(time.Now().Unix())}, nil
+}
+
+func (c *nlConn) close() {
+	unix.Close(c.fd)
+}
+
+// join subscribes to a multicast group.
+func (c *nlConn) join(group uint32) error {
+	if err := unix.SetsockoptInt(c.fd, unix.SOL_NETLINK, unix.NETLINK_ADD_MEMBERSHIP, int(group)); err != nil {
+		return fmt.Errorf("netlink join group: %w", err)
+	}
+	return nil
+}
+
+// send writes one generic netlink request and returns its sequence number.
+func (c *nlConn) send(family uint16, cmd uint8, flags uint16, attrs []byte) (uint32, error) {
+	c.seq++
+	msg := make([]byte, nlmsgHdrLen+genlHdrLen, nlmsgHdrLen+genlHdrLen+len(attrs))
+	msg = append(msg, attrs...)
+	nlEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	nlEndian.PutUint16(msg[4:6], family)
+	nlEndian.PutUint16(msg[6:8], nlmFRequest|flags)
+	nlEndian.PutUint32(msg[8:12], c.seq)
+	msg[nlmsgHdrLen] = cmd
+	msg[nlmsgHdrLen+1] = 1 // genl version
+
+	if err := unix.Sendto(c.fd, msg, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return 0, fmt.Errorf("netlink send: %w", err)
+	}
+	return c.seq, nil
+}
+
+// recv reads one datagram, which may hold several messages.
+func (c *nlConn) recv() ([]byte, error) {
+	buf := make([]byte, nlRecvBufSize)
+	n, _, err := unix.Recvfrom(c.fd, buf, 0)
+	if err != nil {
+		if errors.Is(err, unix.EAGAIN) {
+			return nil, fmt.Errorf("netlink: timed out waiting for kernel")
+		}
+		return nil, fmt.Errorf("netlink recv: %w", err)
+	}
+	return buf[:n], nil
+}
+
+// request sends a command and waits for its ACK or error.
+func (c *nlConn) request(family uint16, cmd uint8, flags uint16, attrs []byte) error {
+	seq, err := c.send(family, cmd, flags, attrs)
+	if err != nil {
+		return err
+	}
+	for {
+		buf, err := c.recv()
+		if err != nil {
+			return err
+		}
+		msgs, err := parseNetlinkMessages(buf)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			if m.Seq == seq && m.Type == nlmsgError {
+				return m.errno()
+			}
+		}
+	}
+}
+
+// dump sends a dump request and returns every reply message, raw, up to and
+// including NLMSG_DONE.
+func (c *nlConn) dump(family uint16, cmd uint8, attrs []byte) ([]byte, error) {
+	seq, err := c.send(family, cmd, nlmFDump, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []byte
+	for {
+		buf, err := c.recv()
+		if err != nil {
+			return nil, err
+		}
+		msgs, err := parseNetlinkMessages(buf)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range msgs {
+			if m.Seq != seq {
+				continue // multicast notification
+			}
+			out = appendMessage(out, m)
+			switch m.Type {
+			case nlmsgDone:
+				return out, nil
+			case nlmsgError:
+				if err := m.errno(); err != nil {
+					return nil, fmt.Errorf("netlink dump: %w", err)
+				}
+				return out, nil
+			}
+		}
+	}
+}
+
+// waitScan blocks until the kernel reports the scan on ifindex finished.
+func (c *nlConn) waitScan(ifindex uint32) error {
+	for {
+		buf, err := c.recv()
+		if err != nil {
+			return err
+		}
+		msgs, err := parseNetlinkMessages(buf)
+		if err != nil {
+			return err
+		}
+		for _, m := range msgs {
+			cmd, attrs := m.genlAttrs()
+			if v := attrs[nl80211AttrIfindex]; len(v) < 4 || nlEndian.Uint32(v) != ifindex {
+				continue
+			}
+			switch cmd {
+			case nl80211CmdNewScanResults:
+				return nil
+			case nl80211CmdScanAborted:
+				return fmt.Errorf("scan aborted by kernel")
+			}
+		}
+	}
+}
+
+// resolveFamily looks up a generic netlink family's ID and multicast groups.
+func (c *nlConn) resolveFamily(name string) (uint16, map[string]uint32, error) {
+	attrs := appendAttr(nil, ctrlAttrFamilyName, append([]byte(name), 0))
+	seq, err := c.send(genlIDCtrl, ctrlCmdGetFam, 0, attrs)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for {
+		buf, err := c.recv()
+		if err != nil {
+			return 0, nil, err
+		}
+		msgs, err := parseNetlinkMessages(buf)
+		if err != nil {
+			return 0, nil, err
+		}
+		for _, m := range msgs {
+			if m.Seq != seq {
+				continue
+			}
+			if m.Type == nlmsgError {
+				if err := m.errno(); err != nil {
+					if errors.Is(err, unix.ENOENT) {
+						return 0, nil, fmt.Errorf("%s not available: %w", name, os.ErrNotExist)
+					}
+					return 0, nil, fmt.Errorf("resolve %s: %w", name, err)
+				}
+				continue
+			}
+			_, fa := m.genlAttrs()
+			id := fa[ctrlAttrFamilyID]
+			if len(id) < 2 {
+				return 0, nil, fmt.Errorf("resolve %s: missing family id", name)
+			}
+			groups := make(map[string]uint32)
+			for _, g := range parseAttrList(fa[ctrlAttrMcastGroups]) {
+				ga := parseAttrs(g)
+				gname, gid := ga[ctrlAttrMcastName], ga[ctrlAttrMcastID]
+				if len(gname) > 1 && len(gid) >= 4 {
+					groups[string(gname[:len(gname)-1])] = nlEndian.Uint32(gid)
+				}
+			}
+			return nlEndian.Uint16(id), groups, nil
+		}
+	}
+}
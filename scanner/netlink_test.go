@@ -0,0 +1,207 @@
+package scanner
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func nlU8(v uint8) []byte { return []byte{v} }
+
+func nlU32(v uint32) []byte {
+	b := make([]byte, 4)
+	nlEndian.PutUint32(b, v)
+	return b
+}
+
+func nlU64(v uint64) []byte {
+	b := make([]byte, 8)
+	nlEndian.PutUint64(b, v)
+	return b
+}
+
+// genlMessage builds one generic netlink message as the kernel sends it in
+// a dump: the genl header followed by attrs.
+func genlMessage(buf []byte, cmd uint8, attrs []byte) []byte {
+	payload := append([]byte{cmd, 1, 0, 0}, attrs...)
+	return appendMessage(buf, nlMessage{Type: 0x1c, Flags: nlmFMulti, Seq: 7, Payload: payload})
+}
+
+func nlDone(buf []byte) []byte {
+	return appendMessage(buf, nlMessage{Type: nlmsgDone, Flags: nlmFMulti, Seq: 7, Payload: nlU32(0)})
+}
+
+func nlError(buf []byte, errno syscall.Errno) []byte {
+	return appendMessage(buf, nlMessage{Type: nlmsgError, Seq: 7, Payload: nlU32(uint32(-int32(errno)))})
+}
+
+// scanResult builds a NEW_SCAN_RESULTS message for one BSS.
+func scanResult(buf []byte, bss ...[]byte) []byte {
+	var nested []byte
+	for _, a := range bss {
+		nested = append(nested, a...)
+	}
+	return genlMessage(buf, nl80211CmdNewScanResults, appendAttr(nil, nl80211AttrBSS|nlaFNested, nested))
+}
+
+func TestParseNetlinkScanDump(t *testing.T) {
+	homeIEs := []byte{
+		ieSSID, 7, 'H', 'o', 'm', 'e', 'N', 'e', 't',
+		ieDSParams, 1, 6,
+	}
+	var dump []byte
+	dump = scanResult(dump,
+		appendAttr(nil, nl80211BSSBSSID, []byte{0xa4, 0x2b, 0x8c, 0x01, 0x02, 0x03}),
+		appendAttr(nil, nl80211BSSFrequency, nlU32(2437)),
+		appendAttr(nil, nl80211BSSTSF, nlU64(3723000000)),
+		appendAttr(nil, nl80211BSSCapabilty, []byte{0x11, 0x04}),
+		appendAttr(nil, nl80211BSSSignalMBM, nlU32(uint32(0xffffffff-5200+1))), // -5200 mBm
+		appendAttr(nil, nl80211BSSIEs, homeIEs),
+	)
+	dump = scanResult(dump,
+		appendAttr(nil, nl80211BSSBSSID, []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}),
+		appendAttr(nil, nl80211BSSFrequency, nlU32(5180)),
+		appendAttr(nil, nl80211BSSSignalUnspec, nlU8(50)),
+		appendAttr(nil, nl80211BSSBeaconIEs, []byte{ieSSID, 0}),
+	)
+	// A BSS without a valid BSSID is dropped
+	dump = scanResult(dump, appendAttr(nil, nl80211BSSBSSID, []byte{1, 2, 3}))
+	dump = nlDone(dump)
+
+	before := time.Now()
+	networks, err := parseNetlinkScanDump(dump)
+	if err != nil {
+		t.Fatalf("parseNetlinkScanDump: %v", err)
+	}
+	if len(networks) != 2 {
+		t.Fatalf("got %d networks, want 2", len(networks))
+	}
+
+	home := networks[0]
+	if home.BSSID != "A4:2B:8C:01:02:03" {
+		t.Errorf("BSSID = %q, want A4:2B:8C:01:02:03", home.BSSID)
+	}
+	if home.SSID != "HomeNet" || home.Frequency != 2437 || home.Channel != 6 {
+		t.Errorf("got SSID %q freq %d channel %d, want HomeNet 2437 6", home.SSID, home.Frequency, home.Channel)
+	}
+	if home.Signal != -52 {
+		t.Errorf("mBm signal = %d, want -52", home.Signal)
+	}
+	if home.TSF != 3723000000 {
+		t.Errorf("TSF = %d, want 3723000000", home.TSF)
+	}
+	if !bytes.Equal(home.IEs, homeIEs) {
+		t.Errorf("IEs = % x, want % x", home.IEs, homeIEs)
+	}
+	if home.LastSeen.Before(before) {
+		t.Errorf("LastSeen %v predates the parse", home.LastSeen)
+	}
+
+	hidden := networks[1]
+	if hidden.BSSID != "00:11:22:33:44:55" || hidden.SSID != "<hidden>" {
+		t.Errorf("got %s %q, want 00:11:22:33:44:55 <hidden>", hidden.BSSID, hidden.SSID)
+	}
+	if hidden.Signal != percentToDBm(50) {
+		t.Errorf("unspec signal = %d, want %d", hidden.Signal, percentToDBm(50))
+	}
+	if hidden.Channel != 36 {
+		t.Errorf("channel from frequency = %d, want 36", hidden.Channel)
+	}
+	if hidden.TSF != 0 {
+		t.Errorf("TSF = %d, want 0 when not reported", hidden.TSF)
+	}
+}
+
+func TestParseNetlinkScanDumpError(t *testing.T) {
+	tests := []struct {
+		name    string
+		dump    []byte
+		wantErr error
+		want    int
+	}{
+		{
+			name:    "error",
+			dump:    nlError(nil, syscall.ENETDOWN),
+			wantErr: syscall.ENETDOWN,
+		},
+		{
+			name: "ack",
+			dump: nlError(scanResult(nil, appendAttr(nil, nl80211BSSBSSID, make([]byte, 6))), 0),
+			want: 1,
+		},
+		{
+			name: "empty",
+			dump: nlDone(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := parseNetlinkScanDump(tt.dump)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(networks) != tt.want {
+				t.Errorf("got %d networks, want %d", len(networks), tt.want)
+			}
+		})
+	}
+
+	// A message claiming to run past the buffer is rejected
+	bad := nlDone(nil)
+	nlEndian.PutUint32(bad, 64)
+	if _, err := parseNetlinkScanDump(bad); err == nil {
+		t.Error("truncated dump parsed without error")
+	}
+}
+
+func TestParseNetlinkSurveyDump(t *testing.T) {
+	survey := func(buf []byte, info ...[]byte) []byte {
+		var nested []byte
+		for _, a := range info {
+			nested = append(nested, a...)
+		}
+		return genlMessage(buf, nl80211CmdNewSurvey, appendAttr(nil, nl80211AttrSurvey|nlaFNested, nested))
+	}
+
+	var dump []byte
+	dump = survey(dump,
+		appendAttr(nil, nl80211SurveyFrequency, nlU32(2412)),
+		appendAttr(nil, nl80211SurveyInUse, nil),
+		appendAttr(nil, nl80211SurveyNoise, nlU8(uint8(0x100-95))), // -95 dBm
+		appendAttr(nil, nl80211SurveyTime, nlU64(1000)),
+		appendAttr(nil, nl80211SurveyTimeBusy, nlU64(250)),
+		appendAttr(nil, nl80211SurveyTimeRx, nlU64(200)),
+		appendAttr(nil, nl80211SurveyTimeTx, nlU64(30)),
+	)
+	dump = survey(dump, appendAttr(nil, nl80211SurveyFrequency, nlU32(5180)))
+	// Entries without a frequency are skipped
+	dump = survey(dump, appendAttr(nil, nl80211SurveyNoise, nlU8(0)))
+	dump = nlDone(dump)
+
+	surveys, err := parseNetlinkSurveyDump("wlan0", dump)
+	if err != nil {
+		t.Fatalf("parseNetlinkSurveyDump: %v", err)
+	}
+	want := []ChannelSurvey{
+		{
+			Interface: "wlan0", Frequency: 2412, InUse: true, Noise: -95,
+			Active: time.Second, Busy: 250 * time.Millisecond,
+			Receive: 200 * time.Millisecond, Transmit: 30 * time.Millisecond,
+		},
+		{Interface: "wlan0", Frequency: 5180},
+	}
+	if len(surveys) != len(want) {
+		t.Fatalf("got %d surveys, want %d", len(surveys), len(want))
+	}
+	for i := range want {
+		if surveys[i] != want[i] {
+			t.Errorf("survey %d = %+v, want %+v", i, surveys[i], want[i])
+		}
+	}
+
+	if _, err := parseNetlinkSurveyDump("wlan0", nlError(nil, syscall.EOPNOTSUPP)); !errors.Is(err, syscall.EOPNOTSUPP) {
+		t.Errorf("err = %v, want EOPNOTSUPP", err)
+	}
+}
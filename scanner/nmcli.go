@@ -0,0 +1,251 @@
+package scanner
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"wifiscanner/scanner/channel"
+)
+
+func init() {
+	register("nmcli", func() Backend { return &nmcliBackend{} })
+}
+
+// nmcliFields is the column list requested from 'nmcli dev wifi list'.
+const nmcliFields = "BSSID,SSID,CHAN,FREQ,SIGNAL,SECURITY,WPA-FLAGS,RSN-FLAGS"
+
+// nmcliBackend reads scan results from NetworkManager, which scans on the
+// user's behalf and so needs no root privileges.
+type nmcliBackend struct{}
+
+func (b *nmcliBackend) Name() string { return "nmcli" }
+
+func (b *nmcliBackend) Capabilities() Capabilities {
+	return CapActiveScan
+}
+
+// Scan asks NetworkManager for a fresh scan, falling back to its cached
+// results if the rescan is refused (e.g. by polkit or rate limiting).
+func (b *nmcliBackend) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+// ScanRaw is Scan that also returns nmcli's terse output.
+func (b *nmcliBackend) ScanRaw(iface string) ([]byte, []Network, error) {
+	out, err := nmcliList(iface, "yes")
+	if err != nil {
+		out, err = nmcliList(iface, "no")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, parseNmcliOutput(string(out)), nil
+}
+
+func nmcliList(iface, rescan string) ([]byte, error) {
+	args := []string{"-t", "-f", nmcliFields, "dev", "wifi", "list", "--rescan", rescan}
+	if iface != "" {
+		args = append(args, "ifname", iface)
+	}
+	out, err := exec.Command("nmcli", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("scan failed: nmcli: %w", err)
+	}
+	return out, nil
+}
+
+// Interfaces lists the WiFi devices NetworkManager manages.
+func (b *nmcliBackend) Interfaces() ([]string, error) {
+	out, err := exec.Command("nmcli", "-t", "-f", "DEVICE,TYPE", "device").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run 'nmcli device': %w", err)
+	}
+
+	var ifaces []string
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := splitNmcliTerse(line)
+		if len(fields) == 2 && fields[1] == "wifi" {
+			ifaces = append(ifaces, fields[0])
+		}
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no WiFi device managed by NetworkManager")
+	}
+	return ifaces, nil
+}
+
+// parseNmcliOutput parses terse 'nmcli dev wifi list' output in the
+// nmcliFields column order.
+func parseNmcliOutput(output string) []Network {
+	var networks []Network
+	now := time.Now()
+
+	for _, line := range strings.Split(output, "\n") {
+		fields := splitNmcliTerse(line)
+		if len(fields) != 8 {
+			continue
+		}
+
+		n := Network{
+			BSSID:        strings.ToUpper(fields[0]),
+			SSID:         fields[1],
+			SecurityInfo: nmcliSecurity(fields[5], fields[6], fields[7]),
+			LastSeen:     now,
+		}
+		n.Security = n.SecurityInfo.Class()
+		if nmcliOWETransition(fields[7]) {
+			n.OWETransition = &OWETransition{}
+		}
+		if n.SSID == "" {
+			n.SSID = "<hidden>"
+		}
+		n.Channel, _ = strconv.Atoi(fields[2])
+		n.Frequency, _ = strconv.Atoi(strings.TrimSuffix(fields[3], " MHz"))
+		if pct, err := strconv.Atoi(fields[4]); err == nil {
+			n.Signal = percentToDBm(pct)
+		}
+		if n.Channel == 0 {
+			n.Channel = channel.Number(n.Frequency)
+		}
+
+		networks = append(networks, n)
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Signal > networks[j].Signal
+	})
+
+	return networks
+}
+
+// splitNmcliTerse splits one line of nmcli terse output on unescaped
+// colons, removing the backslash escapes nmcli adds to ':' and '\'.
+func splitNmcliTerse(line string) []string {
+	if line == "" {
+		return nil
+	}
+
+	var fields []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+		case c == ':':
+			fields = append(fields, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(fields, cur.String())
+}
+
+// percentToDBm inverts NetworkManager's signal quality mapping
+// (nl80211_xbm_to_percent), which clamps to -90..-20 dBm and scales that
+// linearly onto 30..100%, so one percent is one dB.
+func percentToDBm(pct int) int {
+	if pct < 30 {
+		pct = 30
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	return pct - 120
+}
+
+// nmcliCiphers maps NetworkManager's cipher flag suffixes to the names iw
+// prints.
+var nmcliCiphers = map[string]string{
+	"wep40":  "WEP-40",
+	"wep104": "WEP-104",
+	"tkip":   "TKIP",
+	"ccmp":   "CCMP",
+}
+
+// nmcliAKMs maps NetworkManager's key management flags to normalised names.
+var nmcliAKMs = map[string]string{
+	"psk":             "PSK",
+	"802.1X":          "802.1X",
+	"sae":             "SAE",
+	"owe":             "OWE",
+	"eap_suite_b_192": "SuiteB-192",
+}
+
+// nmcliSecurity builds a SecurityInfo from NetworkManager's WPA-FLAGS and
+// RSN-FLAGS columns (e.g. "pair_ccmp group_ccmp psk sae"), using the
+// SECURITY column only to spot WEP. NetworkManager does not report PMF.
+func nmcliSecurity(security, wpaFlags, rsnFlags string) SecurityInfo {
+	var si SecurityInfo
+	if flags := nmcliFlags(rsnFlags); len(flags) > 0 {
+		si.RSN = true
+		si.mergeNmcli(flags)
+	}
+	if flags := nmcliFlags(wpaFlags); len(flags) > 0 {
+		si.WPA = true
+		si.mergeNmcli(flags)
+	}
+	for _, f := range strings.Fields(security) {
+		if f == "WEP" {
+			si.Privacy = true
+		}
+	}
+	return si
+}
+
+// nmcliFlags splits a flags column, dropping owe_tm: it marks the open half
+// of an OWE transition pair rather than an RSN element.
+func nmcliFlags(s string) []string {
+	if s == "(none)" || s == "--" {
+		return nil
+	}
+	var flags []string
+	for _, f := range strings.Fields(s) {
+		if f != nmcliOWETM {
+			flags = append(flags, f)
+		}
+	}
+	return flags
+}
+
+// nmcliOWETM is the RSN flag NetworkManager sets on an open BSS that
+// advertises an OWE transition partner.
+const nmcliOWETM = "owe_tm"
+
+// nmcliOWETransition reports whether rsnFlags carries owe_tm.
+// NetworkManager does not report the partner's BSSID or SSID.
+func nmcliOWETransition(rsnFlags string) bool {
+	for _, f := range strings.Fields(rsnFlags) {
+		if f == nmcliOWETM {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeNmcli merges one nmcli flag list into si. RSN flags are merged first,
+// so an RSN group cipher wins over a WPA one.
+func (si *SecurityInfo) mergeNmcli(flags []string) {
+	for _, f := range flags {
+		switch {
+		case strings.HasPrefix(f, "pair_"):
+			if c, ok := nmcliCiphers[strings.TrimPrefix(f, "pair_")]; ok {
+				si.PairwiseCiphers = addUnique(si.PairwiseCiphers, c)
+			}
+		case strings.HasPrefix(f, "group_"):
+			if c, ok := nmcliCiphers[strings.TrimPrefix(f, "group_")]; ok && si.GroupCipher == "" {
+				si.GroupCipher = c
+			}
+		default:
+			if akm, ok := nmcliAKMs[f]; ok {
+				si.AKMs = addUnique(si.AKMs, akm)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitNmcliTerse(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"wlan0:wifi", []string{"wlan0", "wifi"}},
+		{`A4\:2B\:8C\:01\:02\:03:HomeNet`, []string{"A4:2B:8C:01:02:03", "HomeNet"}},
+		{`A4\:2B\:8C\:01\:02\:03:Cafe\: Free:6`, []string{"A4:2B:8C:01:02:03", "Cafe: Free", "6"}},
+		{`back\\slash:x`, []string{`back\slash`, "x"}},
+		{`ends in \\:x`, []string{`ends in \`, "x"}},
+		{`\\\::x`, []string{`\:`, "x"}},
+		{"a::c", []string{"a", "", "c"}},
+		{":", []string{"", ""}},
+		{"a:", []string{"a", ""}},
+		{`trailing\`, []string{`trailing\`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := splitNmcliTerse(tt.line); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitNmcliTerse(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNmcliOutput(t *testing.T) {
+	output := `A4\:2B\:8C\:01\:02\:03:HomeNet:6:2437 MHz:80:WPA2 WPA3:(none):pair_ccmp group_ccmp psk sae
+00\:11\:22\:33\:44\:55:Cafe\: Free \\o/:36:5180 MHz:45:::
+b0\:c7\:45\:3a\:91\:de::0:5500 MHz:100:WPA1 WPA2:pair_tkip group_tkip psk:pair_ccmp group_tkip psk
+not:enough:fields
+`
+	networks := parseNmcliOutput(output)
+	if len(networks) != 3 {
+		t.Fatalf("got %d networks, want 3", len(networks))
+	}
+
+	tests := []struct {
+		bssid    string
+		ssid     string
+		channel  int
+		freq     int
+		signal   int
+		security string
+	}{
+		{"B0:C7:45:3A:91:DE", "<hidden>", 100, 5500, -20, "WPA2/WPA"},
+		{"A4:2B:8C:01:02:03", "HomeNet", 6, 2437, -40, "WPA3"},
+		{"00:11:22:33:44:55", `Cafe: Free \o/`, 36, 5180, -75, "OPEN"},
+	}
+	for i, tt := range tests {
+		n := networks[i]
+		if n.BSSID != tt.bssid || n.SSID != tt.ssid {
+			t.Errorf("network %d = %s %q, want %s %q", i, n.BSSID, n.SSID, tt.bssid, tt.ssid)
+		}
+		if n.Channel != tt.channel || n.Frequency != tt.freq || n.Signal != tt.signal {
+			t.Errorf("%s: channel %d freq %d signal %d, want %d %d %d",
+				tt.bssid, n.Channel, n.Frequency, n.Signal, tt.channel, tt.freq, tt.signal)
+		}
+		if n.Security != tt.security {
+			t.Errorf("%s: security %s, want %s", tt.bssid, n.Security, tt.security)
+		}
+	}
+}
+
+func TestPercentToDBm(t *testing.T) {
+	tests := []struct{ pct, dbm int }{
+		{100, -20},
+		{80, -40},
+		{30, -90},
+		{0, -90},
+		{120, -20},
+	}
+	for _, tt := range tests {
+		if got := percentToDBm(tt.pct); got != tt.dbm {
+			t.Errorf("percentToDBm(%d) = %d, want %d", tt.pct, got, tt.dbm)
+		}
+	}
+}
+
+func TestNmcliOWETransition(t *testing.T) {
+	output := `02\:11\:22\:33\:44\:55:Cafe:6:2437 MHz:70::(none):owe_tm
+0A\:11\:22\:33\:44\:55::6:2437 MHz:68:OWE:(none):pair_ccmp group_ccmp owe
+`
+	networks := parseNmcliOutput(output)
+	if len(networks) != 2 {
+		t.Fatalf("got %d networks, want 2", len(networks))
+	}
+
+	open := networks[0]
+	if open.SecurityInfo.RSN || open.Security != "OPEN" {
+		t.Errorf("open half: RSN %v security %s, want no RSN and OPEN", open.SecurityInfo.RSN, open.Security)
+	}
+	if open.OWETransition == nil {
+		t.Error("open half: OWETransition not set")
+	}
+
+	owe := networks[1]
+	if owe.Security != "OWE" || owe.OWETransition != nil {
+		t.Errorf("OWE half: security %s transition %+v, want OWE and none", owe.Security, owe.OWETransition)
+	}
+}
@@ -0,0 +1,137 @@
+package scanner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OWE Transition Mode vendor element (Wi-Fi Alliance OUI, type 0x1C).
+const (
+	ouiWFA           = 0x506F9A
+	wfaTypeOWETrans  = 0x1C
+	oweTransMinBytes = 7 // BSSID + SSID length
+)
+
+// OWETransition links the open and OWE halves of an Enhanced Open
+// transition-mode network. Each BSS advertises the other.
+type OWETransition struct {
+	BSSID   string // partner BSSID, empty when the backend does not report it
+	SSID    string // partner SSID (the OWE half is usually hidden)
+	Channel int    // partner channel, 0 when on the same channel
+
+	// Partner is the partner's scan entry, set by GroupOWETransition when
+	// both halves were seen.
+	Partner *Network
+}
+
+// decodeOWETransition parses an OWE Transition Mode element body (after the
+// OUI and type bytes): BSSID, SSID length, SSID, then optional band and
+// channel.
+func decodeOWETransition(data []byte) *OWETransition {
+	if len(data) < oweTransMinBytes {
+		return nil
+	}
+	ssidLen := int(data[6])
+	if len(data) < oweTransMinBytes+ssidLen {
+		return nil
+	}
+
+	t := &OWETransition{
+		BSSID: fmt.Sprintf("%02X:%02X:%02X:%02X:%02X:%02X", data[0], data[1], data[2], data[3], data[4], data[5]),
+		SSID:  escapeSSID(data[7 : 7+ssidLen]),
+	}
+	if rest := data[7+ssidLen:]; len(rest) >= 2 {
+		t.Channel = int(rest[1])
+	}
+	return t
+}
+
+// parseOWETransition reads the "OWE Transition Mode" section iw prints, or
+// the raw WFA vendor element older iw versions dump with -u.
+func parseOWETransition(block string) *OWETransition {
+	if lines, ok := iwSection(block, "OWE Transition Mode"); ok {
+		fields := iwFields(lines)
+		if len(fields["BSSID"]) != 17 {
+			return nil
+		}
+		t := &OWETransition{BSSID: strings.ToUpper(fields["BSSID"]), SSID: fields["SSID"]}
+		t.Channel, _ = strconv.Atoi(fields["Channel Info"])
+		return t
+	}
+
+	for _, e := range iwVendorElements(block) {
+		if oui, typ, ok := e.vendorOUI(); ok && oui == ouiWFA && typ == wfaTypeOWETrans {
+			return decodeOWETransition(e.Data[4:])
+		}
+	}
+	return nil
+}
+
+// GroupOWETransition collapses each OWE transition pair whose halves were
+// both seen into a single logical network. The entry keeps the open BSS's
+// identity, which is what clients display, takes the OWE BSS's security and
+// links the OWE BSS as Partner. It takes the position of whichever half
+// came first; unpaired networks are returned unchanged.
+func GroupOWETransition(networks []Network) []Network {
+	index := make(map[string]int, len(networks))
+	for i, n := range networks {
+		index[n.BSSID] = i
+	}
+
+	out := make([]Network, 0, len(networks))
+	consumed := make(map[string]bool)
+
+	for _, n := range networks {
+		if consumed[n.BSSID] {
+			continue
+		}
+
+		open, owe, ok := owePair(n, networks, index)
+		if !ok {
+			out = append(out, n)
+			continue
+		}
+
+		partner := owe
+		merged := open
+		merged.Security = "OWE-TM"
+		merged.SecurityInfo = owe.SecurityInfo
+		merged.OWETransition = &OWETransition{
+			BSSID:   owe.BSSID,
+			SSID:    open.OWETransition.SSID,
+			Channel: open.OWETransition.Channel,
+			Partner: &partner,
+		}
+
+		consumed[open.BSSID] = true
+		consumed[owe.BSSID] = true
+		out = append(out, merged)
+	}
+
+	return out
+}
+
+// owePair returns n and its transition partner ordered as (open, OWE) when
+// both were seen and advertise each other.
+func owePair(n Network, networks []Network, index map[string]int) (Network, Network, bool) {
+	if n.OWETransition == nil {
+		return Network{}, Network{}, false
+	}
+	i, ok := index[n.OWETransition.BSSID]
+	if !ok {
+		return Network{}, Network{}, false
+	}
+	p := networks[i]
+	if p.OWETransition == nil || p.OWETransition.BSSID != n.BSSID {
+		return Network{}, Network{}, false
+	}
+
+	switch {
+	case n.Security == "OPEN" && p.Security == "OWE":
+		return n, p, true
+	case n.Security == "OWE" && p.Security == "OPEN":
+		return p, n, true
+	}
+	return Network{}, Network{}, false
+}
@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeOWETransition(t *testing.T) {
+	bssid := []byte{0x0a, 0x11, 0x22, 0x33, 0x44, 0x55}
+	tests := []struct {
+		name string
+		data []byte
+		want *OWETransition
+	}{
+		{
+			"band and channel",
+			append(append(append([]byte(nil), bssid...), 4), append([]byte("Cafe"), 81, 6)...),
+			&OWETransition{BSSID: "0A:11:22:33:44:55", SSID: "Cafe", Channel: 6},
+		},
+		{
+			"hidden partner",
+			append(append([]byte(nil), bssid...), 0),
+			&OWETransition{BSSID: "0A:11:22:33:44:55"},
+		},
+		{"truncated", bssid, nil},
+		{"SSID overrun", append(append([]byte(nil), bssid...), 4, 'C', 'a'), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeOWETransition(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeOWETransition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOWETransition(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  *OWETransition
+	}{
+		{
+			"iw section",
+			`BSS 02:11:22:33:44:55(on wlan0)
+	SSID: Cafe
+	OWE Transition Mode:
+		BSSID: 0a:11:22:33:44:55
+		SSID:
+		Band Info: 115
+		Channel Info: 36
+`,
+			&OWETransition{BSSID: "0A:11:22:33:44:55", Channel: 36},
+		},
+		{
+			"vendor dump",
+			`BSS 0a:11:22:33:44:55(on wlan0)
+	SSID:
+	WFA 0x1c, data: 02 11 22 33 44 55 04 43 61 66 65
+`,
+			&OWETransition{BSSID: "02:11:22:33:44:55", SSID: "Cafe"},
+		},
+		{
+			"bad BSSID",
+			`BSS 02:11:22:33:44:55(on wlan0)
+	OWE Transition Mode:
+		BSSID: 0a:11:22
+`,
+			nil,
+		},
+		{"absent", "BSS 02:11:22:33:44:55(on wlan0)\n\tSSID: Cafe\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseOWETransition(tt.block); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOWETransition() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupOWETransition(t *testing.T) {
+	oweSec := SecurityInfo{RSN: true, AKMs: []string{"OWE"}, PMFRequired: true, PMFCapable: true}
+	networks := []Network{
+		{BSSID: "0A:11:22:33:44:55", SSID: "<hidden>", Security: "OWE", SecurityInfo: oweSec,
+			OWETransition: &OWETransition{BSSID: "02:11:22:33:44:55", SSID: "Cafe"}},
+		{BSSID: "AA:BB:CC:00:00:01", SSID: "Other", Security: "WPA2"},
+		{BSSID: "02:11:22:33:44:55", SSID: "Cafe", Security: "OPEN",
+			OWETransition: &OWETransition{BSSID: "0A:11:22:33:44:55", Channel: 36}},
+		// Advertises a partner that does not point back
+		{BSSID: "02:11:22:33:44:66", SSID: "Lone", Security: "OPEN",
+			OWETransition: &OWETransition{BSSID: "0A:11:22:33:44:55"}},
+	}
+
+	got := GroupOWETransition(networks)
+	if len(got) != 3 {
+		t.Fatalf("got %d networks, want 3", len(got))
+	}
+	merged := got[0]
+	if merged.BSSID != "02:11:22:33:44:55" || merged.SSID != "Cafe" || merged.Security != "OWE-TM" {
+		t.Errorf("merged = %s %q %s, want the open half labelled OWE-TM", merged.BSSID, merged.SSID, merged.Security)
+	}
+	if !reflect.DeepEqual(merged.SecurityInfo, oweSec) {
+		t.Errorf("merged security = %+v, want the OWE half's", merged.SecurityInfo)
+	}
+	tm := merged.OWETransition
+	if tm == nil || tm.BSSID != "0A:11:22:33:44:55" || tm.Channel != 36 || tm.Partner == nil || tm.Partner.BSSID != tm.BSSID {
+		t.Errorf("merged transition = %+v", tm)
+	}
+	if got[1].BSSID != "AA:BB:CC:00:00:01" || got[2].BSSID != "02:11:22:33:44:66" || got[2].Security != "OPEN" {
+		t.Errorf("unpaired networks = %s %s %s", got[1].BSSID, got[2].BSSID, got[2].Security)
+	}
+}
@@ -0,0 +1,454 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"wifiscanner/scanner/channel"
+)
+
+// PHY-related element IDs.
+const (
+	ieHTCapabilities  = 45
+	ieHTOperation     = 61
+	ieVHTCapabilities = 191
+	ieVHTOperation    = 192
+	ieExtension       = 255
+
+	extHECapabilities  = 35
+	extHEOperation     = 36
+	extEHTOperation    = 106
+	extEHTCapabilities = 108
+)
+
+// PHYInfo summarises a network's HT/VHT/HE/EHT capability and operation
+// elements.
+type PHYInfo struct {
+	HT, VHT, HE, EHT bool // capability element present
+
+	SpatialStreams int  // highest stream count in the RX MCS sets
+	ChannelWidth   int  // operating width in MHz (20 when nothing wider is advertised)
+	ShortGI        bool // 400 ns guard interval (HT/VHT)
+
+	// Highest MCS index per generation: HT counts across streams (0-31),
+	// the others per stream (VHT 7-9, HE 7-11, EHT up to 13).
+	HTMCS, VHTMCS, HEMCS, EHTMCS int
+
+	SecondaryOffset int // HT secondary channel: 1 above, -1 below, 0 none
+	CenterSeg0      int // channel center frequency segment 0 (VHT/HE/EHT operation)
+	CenterSeg1      int // channel center frequency segment 1, 0 if unused
+}
+
+// Standards lists the 802.11 amendments the network supports beyond legacy.
+func (p PHYInfo) Standards() []string {
+	var s []string
+	if p.HT {
+		s = append(s, "802.11n")
+	}
+	if p.VHT {
+		s = append(s, "802.11ac")
+	}
+	if p.HE {
+		s = append(s, "802.11ax")
+	}
+	if p.EHT {
+		s = append(s, "802.11be")
+	}
+	return s
+}
+
+// GuardIntervals lists the supported guard intervals.
+func (p PHYInfo) GuardIntervals() []string {
+	switch {
+	case p.HE || p.EHT:
+		return []string{"0.8µs", "1.6µs", "3.2µs"}
+	case p.ShortGI:
+		return []string{"0.4µs", "0.8µs"}
+	default:
+		return []string{"0.8µs"}
+	}
+}
+
+// Generation returns the Wi-Fi Alliance generation name ("Wi-Fi 6E"), or ""
+// for legacy-only networks.
+func (n Network) Generation() string {
+	p := n.PHY
+	switch {
+	case p.EHT:
+		return "Wi-Fi 7"
+	case p.HE && n.Frequency >= 5925:
+		return "Wi-Fi 6E"
+	case p.HE:
+		return "Wi-Fi 6"
+	case p.VHT:
+		return "Wi-Fi 5"
+	case p.HT:
+		return "Wi-Fi 4"
+	default:
+		return ""
+	}
+}
+
+// widenTo raises the operating width, never lowering it.
+func (p *PHYInfo) widenTo(mhz int) {
+	if mhz > p.ChannelWidth {
+		p.ChannelWidth = mhz
+	}
+}
+
+// setCenters records the center segments of the element that set the
+// widest operating width so far.
+func (p *PHYInfo) setCenters(width, seg0, seg1 int) {
+	if width >= p.ChannelWidth && seg0 != 0 {
+		p.CenterSeg0, p.CenterSeg1 = seg0, seg1
+	}
+}
+
+// vhtOpWidth converts a VHT operation channel width and center segments
+// into MHz. Width 1 with a second segment is the newer 160/80+80 signalling.
+func vhtOpWidth(width, seg0, seg1 int) int {
+	switch width {
+	case 1:
+		if seg1 != 0 {
+			return 160
+		}
+		return 80
+	case 2, 3:
+		return 160
+	default:
+		return 0
+	}
+}
+
+// ── iw text decoding ────────────────────────────────────────────────────────
+
+var (
+	iwStreamsRe  = regexp.MustCompile(`(\d+) streams: MCS 0-(\d+)`)
+	iwNumberRe   = regexp.MustCompile(`\d+`)
+	iwOpWidthRe  = regexp.MustCompile(`^(\d+)`)
+	iwEHTWidthRe = regexp.MustCompile(`(?i)channel width:\s*(\d+)`)
+)
+
+// parsePHY decodes the HT, VHT, HE and EHT sections of an iw BSS block.
+func parsePHY(block string) PHYInfo {
+	p := PHYInfo{ChannelWidth: 20}
+
+	if lines, ok := iwSection(block, "HT capabilities"); ok {
+		p.HT = true
+		for _, l := range lines {
+			switch {
+			case strings.Contains(l, "SGI"):
+				p.ShortGI = true
+			// "HT TX/RX ..." when the TX set matches the RX set
+			case strings.HasPrefix(l, "HT RX MCS rate indexes supported:"),
+				strings.HasPrefix(l, "HT TX/RX MCS rate indexes supported:"):
+				for _, num := range iwNumberRe.FindAllString(l, -1) {
+					if v, _ := strconv.Atoi(num); v < 32 && v > p.HTMCS {
+						p.HTMCS = v
+					}
+				}
+			}
+		}
+		p.SpatialStreams = p.HTMCS/8 + 1
+	}
+
+	if lines, ok := iwSection(block, "HT operation"); ok {
+		f := iwFields(lines)
+		switch f["secondary channel offset"] {
+		case "above":
+			p.SecondaryOffset = 1
+		case "below":
+			p.SecondaryOffset = -1
+		}
+		if p.SecondaryOffset != 0 && f["STA channel width"] == "any" {
+			p.widenTo(40)
+		}
+	}
+
+	if lines, ok := iwSection(block, "VHT capabilities"); ok {
+		p.VHT = true
+		for _, l := range lines {
+			if strings.HasPrefix(l, "short GI") {
+				p.ShortGI = true
+			}
+		}
+		streams, mcs := iwMCSSets(lines)
+		p.VHTMCS = mcs
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+	}
+
+	if lines, ok := iwSection(block, "VHT operation"); ok {
+		f := iwFields(lines)
+		width, seg0, seg1 := iwLeadingInt(f["channel width"]), iwLeadingInt(f["center freq segment 1"]), iwLeadingInt(f["center freq segment 2"])
+		if mhz := vhtOpWidth(width, seg0, seg1); mhz > 0 {
+			p.setCenters(mhz, seg0, seg1)
+			p.widenTo(mhz)
+		}
+	}
+
+	if lines, ok := iwSection(block, "HE capabilities"); ok {
+		p.HE = true
+		streams, mcs := iwMCSSets(lines)
+		p.HEMCS = mcs
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+	}
+
+	if lines, ok := iwSection(block, "HE Operation"); ok {
+		f := iwFields(lines)
+		if w, ok := f["Channel Width"]; ok {
+			mhz := [...]int{20, 40, 80, 160}[iwLeadingInt(w)&3]
+			p.setCenters(mhz, iwLeadingInt(f["Center Frequency Segment 0"]), iwLeadingInt(f["Center Frequency Segment 1"]))
+			p.widenTo(mhz)
+		}
+	}
+
+	if lines, ok := iwSection(block, "EHT capabilities"); ok {
+		p.EHT = true
+		streams, mcs := iwMCSSets(lines)
+		p.EHTMCS = mcs
+		if mcs == 0 {
+			p.EHTMCS = p.HEMCS
+		}
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+	}
+
+	if lines, ok := iwSection(block, "EHT Operation"); ok {
+		for _, l := range lines {
+			if m := iwEHTWidthRe.FindStringSubmatch(l); len(m) > 1 {
+				v, _ := strconv.Atoi(m[1])
+				if v <= 4 {
+					p.widenTo([...]int{20, 40, 80, 160, 320}[v])
+				}
+			}
+		}
+	}
+
+	return p
+}
+
+// iwMCSSets returns the highest stream count and MCS index from the RX
+// "N streams: MCS 0-M" lines of a VHT/HE/EHT capability section.
+func iwMCSSets(lines []string) (streams, mcs int) {
+	rx := false
+	for _, l := range lines {
+		switch {
+		case strings.Contains(l, "RX") && strings.Contains(l, "MCS"):
+			rx = true
+		case strings.Contains(l, "TX") && strings.Contains(l, "MCS"):
+			rx = false
+		}
+		if !rx {
+			continue
+		}
+		if m := iwStreamsRe.FindStringSubmatch(l); len(m) > 2 {
+			s, _ := strconv.Atoi(m[1])
+			v, _ := strconv.Atoi(m[2])
+			if s > streams {
+				streams = s
+			}
+			if v > mcs {
+				mcs = v
+			}
+		}
+	}
+	return streams, mcs
+}
+
+// iwLeadingInt parses the integer at the start of an iw value such as
+// "1 (80 MHz)".
+func iwLeadingInt(s string) int {
+	if m := iwOpWidthRe.FindStringSubmatch(s); len(m) > 1 {
+		v, _ := strconv.Atoi(m[1])
+		return v
+	}
+	return 0
+}
+
+// ── Raw element decoding ────────────────────────────────────────────────────
+
+// decodeElement merges one raw HT/VHT/HE/EHT element into p; other
+// elements are ignored.
+func (p *PHYInfo) decodeElement(e element) {
+	d := e.Data
+	switch e.ID {
+	case ieHTCapabilities:
+		if len(d) < 7 {
+			return
+		}
+		p.HT = true
+		caps := binary.LittleEndian.Uint16(d)
+		p.ShortGI = p.ShortGI || caps&0x0060 != 0
+		// RX MCS bitmask starts after cap info (2) and A-MPDU params (1)
+		for i := 31; i >= 0; i-- {
+			if d[3+i/8]&(1<<(i%8)) != 0 {
+				p.HTMCS = i
+				break
+			}
+		}
+		if s := p.HTMCS/8 + 1; s > p.SpatialStreams {
+			p.SpatialStreams = s
+		}
+
+	case ieHTOperation:
+		if len(d) < 2 {
+			return
+		}
+		switch d[1] & 0x03 {
+		case 1:
+			p.SecondaryOffset = 1
+		case 3:
+			p.SecondaryOffset = -1
+		}
+		if p.SecondaryOffset != 0 && d[1]&0x04 != 0 {
+			p.widenTo(40)
+		}
+
+	case ieVHTCapabilities:
+		if len(d) < 6 {
+			return
+		}
+		p.VHT = true
+		caps := binary.LittleEndian.Uint32(d)
+		p.ShortGI = p.ShortGI || caps&0x0060 != 0
+		streams, mcs := decodeMCSMap(binary.LittleEndian.Uint16(d[4:]), [3]int{7, 8, 9})
+		p.VHTMCS = mcs
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+
+	case ieVHTOperation:
+		if len(d) < 3 {
+			return
+		}
+		if mhz := vhtOpWidth(int(d[0]), int(d[1]), int(d[2])); mhz > 0 {
+			p.setCenters(mhz, int(d[1]), int(d[2]))
+			p.widenTo(mhz)
+		}
+
+	case ieExtension:
+		if len(d) >= 1 {
+			p.decodeExtension(d[0], d[1:])
+		}
+	}
+}
+
+// decodeExtension handles the HE and EHT extension elements.
+func (p *PHYInfo) decodeExtension(ext byte, d []byte) {
+	switch ext {
+	case extHECapabilities:
+		// MAC caps (6) + PHY caps (11) + RX MCS map for <= 80 MHz (2)
+		if len(d) < 19 {
+			return
+		}
+		p.HE = true
+		streams, mcs := decodeMCSMap(binary.LittleEndian.Uint16(d[17:]), [3]int{7, 9, 11})
+		p.HEMCS = mcs
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+
+	case extHEOperation:
+		// Params (3) + BSS color (1) + basic MCS (2), then optional fields
+		if len(d) < 6 {
+			return
+		}
+		params := uint32(d[0]) | uint32(d[1])<<8 | uint32(d[2])<<16
+		off := 6
+		if params&(1<<14) != 0 { // VHT operation information present
+			if len(d) >= off+3 {
+				if mhz := vhtOpWidth(int(d[off]), int(d[off+1]), int(d[off+2])); mhz > 0 {
+					p.setCenters(mhz, int(d[off+1]), int(d[off+2]))
+					p.widenTo(mhz)
+				}
+			}
+			off += 3
+		}
+		if params&(1<<15) != 0 { // co-hosted BSS
+			off++
+		}
+		if params&(1<<17) != 0 && len(d) >= off+5 { // 6 GHz operation information
+			mhz := [...]int{20, 40, 80, 160}[d[off+1]&0x03]
+			p.setCenters(mhz, int(d[off+2]), int(d[off+3]))
+			p.widenTo(mhz)
+		}
+
+	case extEHTCapabilities:
+		// MAC caps (2) + PHY caps (9) + RX/TX MCS map for <= 80 MHz (3).
+		// APs never use the 20 MHz-only map.
+		p.EHT = true
+		if len(d) < 14 {
+			if p.EHTMCS < p.HEMCS {
+				p.EHTMCS = p.HEMCS
+			}
+			return
+		}
+		streams, mcs := decodeEHTMCSMap(d[11:14])
+		p.EHTMCS = mcs
+		if streams > p.SpatialStreams {
+			p.SpatialStreams = streams
+		}
+
+	case extEHTOperation:
+		// Params (1) + basic MCS (4), then operation info if params bit 0
+		if len(d) >= 8 && d[0]&0x01 != 0 {
+			if w := d[5] & 0x07; w <= 4 {
+				mhz := [...]int{20, 40, 80, 160, 320}[w]
+				p.setCenters(mhz, int(d[6]), int(d[7]))
+				p.widenTo(mhz)
+			}
+		}
+	}
+}
+
+// decodeMCSMap reads a VHT/HE MCS map (2 bits per stream, 3 = unsupported)
+// and returns the stream count and highest MCS, given the MCS index each
+// 2-bit value stands for.
+func decodeMCSMap(m uint16, values [3]int) (streams, mcs int) {
+	for i := 0; i < 8; i++ {
+		v := (m >> (2 * i)) & 0x3
+		if v == 3 {
+			continue
+		}
+		streams = i + 1
+		if values[v] > mcs {
+			mcs = values[v]
+		}
+	}
+	return streams, mcs
+}
+
+// ehtMCSLimits are the highest MCS of each EHT-MCS map octet: MCS 0-9,
+// 10-11 and 12-13.
+var ehtMCSLimits = [3]int{9, 11, 13}
+
+// decodeEHTMCSMap reads an EHT-MCS map, one octet per MCS range with the RX
+// max NSS in the low nibble and TX in the high, and returns the highest RX
+// stream count and the highest MCS with any streams.
+func decodeEHTMCSMap(m []byte) (streams, mcs int) {
+	for i, b := range m {
+		nss := int(b & 0x0F)
+		if nss == 0 {
+			continue
+		}
+		if nss > streams {
+			streams = nss
+		}
+		mcs = ehtMCSLimits[i]
+	}
+	return streams, mcs
+}
+
+// Span returns the spectrum the network occupies, from its primary
+// frequency and the PHY operation elements.
+func (n Network) Span() channel.Span {
+	p := n.PHY
+	return channel.New(n.Frequency, p.ChannelWidth, p.SecondaryOffset, p.CenterSeg0, p.CenterSeg1)
+}
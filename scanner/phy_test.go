@@ -0,0 +1,220 @@
+package scanner
+
+import "testing"
+
+func TestDecodeEHTCapabilities(t *testing.T) {
+	caps := make([]byte, 11) // MAC and PHY capabilities
+	tests := []struct {
+		name    string
+		mcsMap  []byte
+		heMCS   int
+		streams int
+		mcs     int
+	}{
+		{"MCS 13", []byte{0x44, 0x44, 0x44}, 11, 4, 13},
+		{"MCS 11", []byte{0x22, 0x22, 0x00}, 11, 2, 11},
+		{"MCS 9", []byte{0x11, 0x00, 0x00}, 9, 1, 9},
+		{"RX streams only", []byte{0x02, 0x02, 0x02}, 11, 2, 13},
+		{"truncated", nil, 11, 0, 11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PHYInfo{HEMCS: tt.heMCS}
+			p.decodeExtension(extEHTCapabilities, append(append([]byte(nil), caps...), tt.mcsMap...))
+			if !p.EHT {
+				t.Error("EHT not set")
+			}
+			if p.EHTMCS != tt.mcs || p.SpatialStreams != tt.streams {
+				t.Errorf("got MCS %d streams %d, want %d %d", p.EHTMCS, p.SpatialStreams, tt.mcs, tt.streams)
+			}
+		})
+	}
+}
+
+// iwScanHE is 'iw scan' output for a two-stream 802.11ax AP on an 80 MHz
+// channel whose HT TX and RX MCS sets match.
+const iwScanHE = `BSS a4:2b:8c:01:02:03(on wlan0)
+	TSF: 1234567890 usec (0d, 00:20:34)
+	freq: 5180
+	beacon interval: 100 TUs
+	capability: ESS Privacy SpectrumMgmt RadioMeasure (0x1111)
+	signal: -52.00 dBm
+	last seen: 120 ms ago
+	SSID: HomeNet
+	Supported rates: 6.0* 9.0 12.0* 18.0 24.0* 36.0 48.0 54.0 
+	DS Parameter set: channel 36
+	HT capabilities:
+		Capabilities: 0x9ef
+			RX LDPC
+			HT20/HT40
+			SM Power Save disabled
+			RX HT20 SGI
+			RX HT40 SGI
+			TX STBC
+			RX STBC 1-stream
+			Max AMSDU length: 7935 bytes
+			No DSSS/CCK HT40
+		Maximum RX AMPDU length 65535 bytes (exponent: 0x003)
+		Minimum RX AMPDU time spacing: 4 usec (0x05)
+		HT TX/RX MCS rate indexes supported: 0-15
+	HT operation:
+		 * primary channel: 36
+		 * secondary channel offset: above
+		 * STA channel width: any
+		 * RIFS: 0
+		 * HT protection: no
+		 * non-GF present: 0
+		 * OBSS non-GF present: 0
+		 * dual beacon: 0
+		 * dual CTS protection: 0
+		 * STBC beacon: 0
+		 * L-SIG TXOP Prot: 0
+		 * PCO active: 0
+		 * PCO phase: 0
+	VHT capabilities:
+		VHT Capabilities (0x0f8b69b2):
+			Max MPDU length: 11454
+			Supported Channel Width: neither 160 nor 80+80
+			RX LDPC
+			short GI (80 MHz)
+			TX STBC
+			SU Beamformer
+			SU Beamformee
+			MU Beamformer
+		VHT RX MCS set:
+			1 streams: MCS 0-9
+			2 streams: MCS 0-9
+			3 streams: not supported
+			4 streams: not supported
+			5 streams: not supported
+			6 streams: not supported
+			7 streams: not supported
+			8 streams: not supported
+		VHT RX highest supported: 0 Mbps
+		VHT TX MCS set:
+			1 streams: MCS 0-9
+			2 streams: MCS 0-9
+			3 streams: not supported
+			4 streams: not supported
+			5 streams: not supported
+			6 streams: not supported
+			7 streams: not supported
+			8 streams: not supported
+		VHT TX highest supported: 0 Mbps
+	VHT operation:
+		 * channel width: 1 (80 MHz)
+		 * center freq segment 1: 42
+		 * center freq segment 2: 0
+		 * VHT basic MCS set: 0xfffc
+	HE capabilities:
+		HE MAC Capabilities (0x000d1a081044):
+			+HTC HE Supported
+			TWT Responder
+			BSR
+			OM Control
+			Maximum A-MPDU Length Exponent: 3
+			A-MSDU in A-MPDU
+			OM Control UL MU Data Disable RX
+		HE PHY Capabilities: (0x0e3f0a0a2b0d8f0c110c00):
+			HE40/HE80/5GHz
+			LDPC Coding in Payload
+			HE SU PPDU with 1x HE-LTF and 0.8us GI
+			STBC Tx <= 80MHz
+			STBC Rx <= 80MHz
+			Full Bandwidth UL MU-MIMO
+			SU Beamformer
+			SU Beamformee
+			MU Beamformer
+			Beamformee STS <= 80Mhz: 3
+			Sounding Dimensions <= 80Mhz: 1
+			Max NC: 1
+		HE RX MCS and NSS set <= 80 MHz
+			1 streams: MCS 0-11
+			2 streams: MCS 0-11
+			3 streams: not supported
+			4 streams: not supported
+			5 streams: not supported
+			6 streams: not supported
+			7 streams: not supported
+			8 streams: not supported
+		HE TX MCS and NSS set <= 80 MHz
+			1 streams: MCS 0-11
+			2 streams: MCS 0-11
+			3 streams: not supported
+			4 streams: not supported
+			5 streams: not supported
+			6 streams: not supported
+			7 streams: not supported
+			8 streams: not supported
+	HE Operation:
+		HE Operation Parameters: (0x003ff4)
+			Default PE Duration: 4
+			TXOP Duration RTS Threshold: 1023
+		BSS Color: 23
+		Basic HE-MCS NSS Set: 0xfffc
+`
+
+// iwScanHT is 'iw scan' output for a three-stream 802.11n AP on 2.4 GHz
+// that leaves its TX MCS set undefined.
+const iwScanHT = `BSS b0:c7:45:3a:91:de(on wlan0)
+	freq: 2437
+	capability: ESS Privacy ShortSlotTime (0x0411)
+	signal: -67.00 dBm
+	SSID: Upstairs
+	Supported rates: 1.0* 2.0* 5.5* 11.0* 6.0 9.0 12.0 18.0 
+	DS Parameter set: channel 6
+	HT capabilities:
+		Capabilities: 0x11ee
+			HT20/HT40
+			SM Power Save disabled
+			RX HT20 SGI
+			RX HT40 SGI
+			TX STBC
+			RX STBC 1-stream
+			Max AMSDU length: 3839 bytes
+			DSSS/CCK HT40
+		Maximum RX AMPDU length 65535 bytes (exponent: 0x003)
+		Minimum RX AMPDU time spacing: 4 usec (0x05)
+		HT RX MCS rate indexes supported: 0-23, 32
+		HT TX MCS rate indexes are undefined
+	HT operation:
+		 * primary channel: 6
+		 * secondary channel offset: no secondary
+		 * STA channel width: 20 MHz
+`
+
+// iwScanLegacy is 'iw scan' output for an 802.11g AP.
+const iwScanLegacy = `BSS 00:11:22:33:44:55(on wlan0)
+	freq: 2412
+	capability: ESS ShortSlotTime (0x0401)
+	signal: -80.00 dBm
+	SSID: Legacy
+	Supported rates: 1.0* 2.0* 5.5* 11.0* 6.0 9.0 12.0 18.0 
+	DS Parameter set: channel 1
+`
+
+func TestParsePHY(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  PHYInfo
+	}{
+		{"HE", iwScanHE, PHYInfo{
+			HT: true, VHT: true, HE: true,
+			SpatialStreams: 2, ChannelWidth: 80, ShortGI: true,
+			HTMCS: 15, VHTMCS: 9, HEMCS: 11,
+			SecondaryOffset: 1, CenterSeg0: 42,
+		}},
+		{"HT", iwScanHT, PHYInfo{
+			HT: true, SpatialStreams: 3, ChannelWidth: 20, ShortGI: true, HTMCS: 23,
+		}},
+		{"legacy", iwScanLegacy, PHYInfo{ChannelWidth: 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePHY(tt.block); got != tt.want {
+				t.Errorf("parsePHY() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package scanner
+
+// Per-MCS coded bits per subcarrier (modulation bits × coding rate), MCS 0-13.
+var mcsBits = [...]float64{0.5, 1, 1.5, 2, 3, 4, 4.5, 5, 6, 20.0 / 3, 7.5, 25.0 / 3, 9, 10}
+
+// mcsSensitivity is the minimum RSSI in dBm needed to decode each MCS on a
+// 20 MHz channel; every doubling of width costs another 3 dB.
+var mcsSensitivity = [...]int{-82, -79, -77, -74, -70, -66, -65, -64, -59, -57, -54, -52, -49, -46}
+
+// legacyRates are the 802.11a/g OFDM rates in Mbps, indexed like MCS 0-7.
+var legacyRates = [...]float64{6, 9, 12, 18, 24, 36, 48, 54}
+
+const (
+	// estimateStreams caps the streams assumed for throughput estimates:
+	// most phones and laptops are 2x2 whatever the AP offers.
+	estimateStreams = 2
+
+	// MAC efficiency: share of the PHY rate left after contention,
+	// headers and acknowledgements. HE/EHT aggregate and schedule better.
+	macEfficiency   = 0.65
+	macEfficiencyHE = 0.75
+)
+
+// MaxPHYRate returns the highest PHY rate in Mbps the network advertises,
+// using its operating width, stream count, top MCS and shortest guard
+// interval.
+func (n Network) MaxPHYRate() float64 {
+	p := n.PHY
+	return phyRate(p, n.topMCS(), p.SpatialStreams)
+}
+
+// EstimatedThroughput returns a realistic throughput in Mbps for a typical
+// client at the network's current Signal: the highest MCS decodable at that
+// RSSI, at most estimateStreams streams, and MAC overhead taken off. Weak
+// links fall back to narrower widths when that pays off, as rate control
+// would. Zero means the signal is too weak for any MCS.
+func (n Network) EstimatedThroughput() float64 {
+	p := n.PHY
+	if p.SpatialStreams > estimateStreams {
+		p.SpatialStreams = estimateStreams
+	}
+
+	efficiency := macEfficiency
+	if p.HE || p.EHT {
+		efficiency = macEfficiencyHE
+	}
+
+	best := 0.0
+	for width := p.ChannelWidth; ; width /= 2 {
+		// Wider channels spread the same power: +3 dB needed per doubling
+		penalty := 0
+		for w := 20; w < width; w *= 2 {
+			penalty += 3
+		}
+		mcs := -1
+		for i := 0; i <= n.topMCS() && i < len(mcsSensitivity); i++ {
+			if n.Signal >= mcsSensitivity[i]+penalty {
+				mcs = i
+			}
+		}
+		narrowed := p
+		narrowed.ChannelWidth = width
+		if r := phyRate(narrowed, mcs, p.SpatialStreams) * efficiency; r > best {
+			best = r
+		}
+		if width <= 20 {
+			return best
+		}
+	}
+}
+
+// topMCS is the highest MCS index the network's newest PHY supports, with
+// HT's cross-stream index folded back to 0-7.
+func (n Network) topMCS() int {
+	p := n.PHY
+	switch {
+	case p.EHT:
+		return p.EHTMCS
+	case p.HE:
+		return p.HEMCS
+	case p.VHT:
+		return p.VHTMCS
+	case p.HT:
+		return p.HTMCS % 8
+	default:
+		return 7
+	}
+}
+
+// phyRate computes the OFDM data rate in Mbps: data subcarriers × coded
+// bits per subcarrier × streams ÷ symbol duration.
+func phyRate(p PHYInfo, mcs, streams int) float64 {
+	if mcs < 0 || mcs >= len(mcsBits) {
+		return 0
+	}
+	if streams < 1 {
+		streams = 1
+	}
+	width := p.ChannelWidth
+	if width < 20 {
+		width = 20
+	}
+
+	var subcarriers, symbol float64
+	switch {
+	case p.HE || p.EHT:
+		// 12.8 µs symbol with the shortest (0.8 µs) HE guard interval
+		subcarriers = map[int]float64{20: 234, 40: 468, 80: 980, 160: 1960, 320: 3920}[width]
+		symbol = 13.6
+	case p.HT || p.VHT:
+		subcarriers = map[int]float64{20: 52, 40: 108, 80: 234, 160: 468}[width]
+		symbol = 4.0
+		if p.ShortGI {
+			symbol = 3.6
+		}
+	default:
+		if mcs >= len(legacyRates) {
+			return 0
+		}
+		return legacyRates[mcs]
+	}
+	return subcarriers * mcsBits[mcs] * float64(streams) / symbol
+}
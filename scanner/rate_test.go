@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"math"
+	"testing"
+)
+
+// iwScanHT40 is 'iw scan' output for a two-stream 802.11n AP on a 40 MHz
+// channel whose HT TX and RX MCS sets match.
+const iwScanHT40 = `BSS 00:11:22:33:44:66(on wlan0)
+	freq: 5200
+	capability: ESS Privacy SpectrumMgmt (0x0111)
+	signal: -60.00 dBm
+	SSID: Garage
+	Supported rates: 6.0* 9.0 12.0* 18.0 24.0* 36.0 48.0 54.0
+	DS Parameter set: channel 40
+	HT capabilities:
+		Capabilities: 0x6e
+			HT20/HT40
+			SM Power Save disabled
+			RX HT20 SGI
+			RX HT40 SGI
+			No RX STBC
+			Max AMSDU length: 3839 bytes
+			No DSSS/CCK HT40
+		Maximum RX AMPDU length 65535 bytes (exponent: 0x003)
+		Minimum RX AMPDU time spacing: 8 usec (0x06)
+		HT TX/RX MCS rate indexes supported: 0-15
+	HT operation:
+		 * primary channel: 40
+		 * secondary channel offset: below
+		 * STA channel width: any
+`
+
+func TestRatesFromIW(t *testing.T) {
+	output := iwScanHE + iwScanHT40 + iwScanHT + iwScanLegacy
+	networks := parseScanOutput(output)
+	if len(networks) != 4 {
+		t.Fatalf("got %d networks, want 4", len(networks))
+	}
+
+	tests := []struct {
+		ssid       string
+		maxRate    float64
+		throughput float64
+	}{
+		// 80 MHz, 2 streams, HE-MCS 11: 980 × 25/3 × 2 ÷ 13.6 µs; MCS 8 at -52 dBm
+		{"HomeNet", 1200.98, 648.53},
+		// 40 MHz, 2 streams, HT MCS 15, short GI
+		{"Garage", 300, 195},
+		// 20 MHz, 3 streams, HT MCS 23, short GI; 2 streams of MCS 4 at -67 dBm
+		{"Upstairs", 216.67, 56.33},
+		{"Legacy", 54, 3.9},
+	}
+	for i, tt := range tests {
+		n := networks[i]
+		if n.SSID != tt.ssid {
+			t.Fatalf("network %d is %q, want %q", i, n.SSID, tt.ssid)
+		}
+		if got := n.MaxPHYRate(); math.Abs(got-tt.maxRate) > 0.01 {
+			t.Errorf("%s: MaxPHYRate() = %.2f, want %.2f", tt.ssid, got, tt.maxRate)
+		}
+		if got := n.EstimatedThroughput(); math.Abs(got-tt.throughput) > 0.01 {
+			t.Errorf("%s: EstimatedThroughput() = %.2f, want %.2f", tt.ssid, got, tt.throughput)
+		}
+	}
+}
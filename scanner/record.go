@@ -0,0 +1,257 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// recordFormat identifies scan log files; recordVersion is bumped on any
+	// change to recordHeader, scanRecord or the Network fields they carry.
+	// Version 2 added survey records and the raw element, TSF, PHY and
+	// roaming fields of Network.
+	recordFormat  = "wifiscanner-record"
+	recordVersion = 2
+
+	// recordKindSurvey marks a record holding channel surveys rather than
+	// a scan. Scan records have no kind.
+	recordKindSurvey = "survey"
+
+	recordMaxBytes   = 16 << 20 // rotate to a new file past this size
+	recordTimeLayout = "20060102T150405"
+	recordExt        = ".jsonl"
+)
+
+// recordHeader is the first line of every scan log file.
+type recordHeader struct {
+	Format  string    `json:"format"`
+	Version int       `json:"version"`
+	Backend string    `json:"backend"`
+	Created time.Time `json:"created"`
+}
+
+// scanRecord is one scan in a log file: what the backend returned, what it
+// was parsed into, and how long it took.
+type scanRecord struct {
+	Kind      string          `json:"kind,omitempty"`
+	Time      time.Time       `json:"time"`
+	Duration  time.Duration   `json:"duration_ns"`
+	Backend   string          `json:"backend"`
+	Interface string          `json:"interface"`
+	Raw       string          `json:"raw,omitempty"`        // text output
+	RawBinary []byte          `json:"raw_binary,omitempty"` // binary output, base64
+	Networks  []Network       `json:"networks,omitempty"`
+	Surveys   []ChannelSurvey `json:"surveys,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// rawBytes returns the recorded backend output whichever field holds it.
+func (r scanRecord) rawBytes() []byte {
+	if r.RawBinary != nil {
+		return r.RawBinary
+	}
+	return []byte(r.Raw)
+}
+
+// recordingBackend wraps a Backend and appends every scan and survey it
+// performs to a rotating JSON-lines log in dir. Files are named by creation
+// time so they sort, and can be replayed, in order.
+type recordingBackend struct {
+	Backend
+	dir string
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRecordingBackend returns backend with every scan and survey recorded
+// under dir. The result implements exactly the optional interfaces backend
+// does, so capability checks on it still hold.
+func NewRecordingBackend(backend Backend, dir string) (Backend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("record: %w", err)
+	}
+	r := &recordingBackend{Backend: backend, dir: dir}
+
+	lister, isLister := backend.(InterfaceLister)
+	sv, isSurveyor := backend.(Surveyor)
+	rr, isReporter := backend.(RegulatoryReporter)
+	survey := recordingSurveyor{r, sv}
+
+	switch {
+	case isLister && isSurveyor && isReporter:
+		return struct {
+			*recordingBackend
+			InterfaceLister
+			Surveyor
+			RegulatoryReporter
+		}{r, lister, survey, rr}, nil
+	case isLister && isSurveyor:
+		return struct {
+			*recordingBackend
+			InterfaceLister
+			Surveyor
+		}{r, lister, survey}, nil
+	case isLister && isReporter:
+		return struct {
+			*recordingBackend
+			InterfaceLister
+			RegulatoryReporter
+		}{r, lister, rr}, nil
+	case isSurveyor && isReporter:
+		return struct {
+			*recordingBackend
+			Surveyor
+			RegulatoryReporter
+		}{r, survey, rr}, nil
+	case isLister:
+		return struct {
+			*recordingBackend
+			InterfaceLister
+		}{r, lister}, nil
+	case isSurveyor:
+		return struct {
+			*recordingBackend
+			Surveyor
+		}{r, survey}, nil
+	case isReporter:
+		return struct {
+			*recordingBackend
+			RegulatoryReporter
+		}{r, rr}, nil
+	default:
+		return r, nil
+	}
+}
+
+// Scan delegates to the wrapped backend and records the outcome, including
+// failed scans. A recording failure is reported as the scan error.
+func (b *recordingBackend) Scan(iface string) ([]Network, error) {
+	start := time.Now()
+
+	var raw []byte
+	var networks []Network
+	var err error
+	if rs, ok := b.Backend.(RawScanner); ok {
+		raw, networks, err = rs.ScanRaw(iface)
+	} else {
+		networks, err = b.Backend.Scan(iface)
+	}
+
+	rec := scanRecord{
+		Time:      start,
+		Duration:  time.Since(start),
+		Backend:   b.Backend.Name(),
+		Interface: iface,
+		Networks:  networks,
+	}
+	if utf8.Valid(raw) {
+		rec.Raw = string(raw)
+	} else {
+		rec.RawBinary = raw
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	if werr := b.write(rec); werr != nil && err == nil {
+		err = werr
+	}
+	return networks, err
+}
+
+// recordingSurveyor records the surveys of a wrapped Surveyor.
+type recordingSurveyor struct {
+	r  *recordingBackend
+	sv Surveyor
+}
+
+// Survey delegates to the wrapped backend and records the surveys.
+// Failures are not recorded: survey data is best effort.
+func (s recordingSurveyor) Survey(iface string) ([]ChannelSurvey, error) {
+	start := time.Now()
+	surveys, err := s.sv.Survey(iface)
+	if err != nil {
+		return nil, err
+	}
+	rec := scanRecord{
+		Kind:      recordKindSurvey,
+		Time:      start,
+		Duration:  time.Since(start),
+		Backend:   s.r.Backend.Name(),
+		Interface: iface,
+		Surveys:   surveys,
+	}
+	if werr := s.r.write(rec); werr != nil {
+		return surveys, werr
+	}
+	return surveys, nil
+}
+
+// write appends rec to the current log file, rotating first if needed.
+func (b *recordingBackend) write(rec scanRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	line = append(line, '\n')
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.file == nil || b.size+int64(len(line)) > recordMaxBytes {
+		if err := b.rotate(rec.Time); err != nil {
+			return err
+		}
+	}
+
+	n, err := b.file.Write(line)
+	b.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the current log file and starts a new one with a header.
+func (b *recordingBackend) rotate(now time.Time) error {
+	if b.file != nil {
+		b.file.Close()
+		b.file = nil
+	}
+
+	name := filepath.Join(b.dir, now.Format(recordTimeLayout)+recordExt)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			break
+		}
+		name = filepath.Join(b.dir, fmt.Sprintf("%s-%d%s", now.Format(recordTimeLayout), i, recordExt))
+	}
+
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("record: %w", err)
+	}
+
+	header, _ := json.Marshal(recordHeader{
+		Format:  recordFormat,
+		Version: recordVersion,
+		Backend: b.Backend.Name(),
+		Created: now,
+	})
+	n, err := f.Write(append(header, '\n'))
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("record: %w", err)
+	}
+
+	b.file = f
+	b.size = int64(n)
+	return nil
+}
@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const recordTestScan = `BSS a4:2b:8c:01:02:03(on wlan0)
+	freq: 2437
+	signal: -48.00 dBm
+	SSID: HomeNet
+	DS Parameter set: channel 6
+BSS 00:11:22:33:44:55(on wlan0)
+	freq: 5180
+	signal: -71.00 dBm
+	SSID: Upstairs
+`
+
+// fakeIW is an "iw" backend that returns canned text and surveys.
+type fakeIW struct{ surveys []ChannelSurvey }
+
+func (b *fakeIW) Name() string               { return "iw" }
+func (b *fakeIW) Capabilities() Capabilities { return CapNeedsRoot }
+
+func (b *fakeIW) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+func (b *fakeIW) ScanRaw(iface string) ([]byte, []Network, error) {
+	return []byte(recordTestScan), parseScanOutput(recordTestScan), nil
+}
+
+func (b *fakeIW) Survey(iface string) ([]ChannelSurvey, error) {
+	return b.surveys, nil
+}
+
+// plainBackend implements no optional interfaces.
+type plainBackend struct{}
+
+func (plainBackend) Name() string                   { return "plain" }
+func (plainBackend) Capabilities() Capabilities     { return 0 }
+func (plainBackend) Scan(string) ([]Network, error) { return nil, nil }
+
+func TestRecordingBackendInterfaces(t *testing.T) {
+	dir := t.TempDir()
+
+	b, err := NewRecordingBackend(&fakeIW{}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(Surveyor); !ok {
+		t.Error("wrapped surveyor is not a Surveyor")
+	}
+	if _, ok := b.(InterfaceLister); ok {
+		t.Error("wrapper is an InterfaceLister, inner backend is not")
+	}
+	if _, ok := b.(RegulatoryReporter); ok {
+		t.Error("wrapper is a RegulatoryReporter, inner backend is not")
+	}
+
+	b, err = NewRecordingBackend(plainBackend{}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := b.(Surveyor); ok {
+		t.Error("wrapped plain backend is a Surveyor")
+	}
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	surveys := []ChannelSurvey{
+		{Interface: "wlan0", Frequency: 2437, InUse: true, Noise: -92, Active: time.Second, Busy: 300 * time.Millisecond},
+		{Interface: "wlan0", Frequency: 5180, Noise: -95, Active: 100 * time.Millisecond},
+	}
+
+	rec, err := NewRecordingBackend(&fakeIW{surveys: surveys}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want [][]Network
+	for i := 0; i < 2; i++ {
+		networks, err := rec.Scan("wlan0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, networks)
+		if _, err := rec.(Surveyor).Survey("wlan0"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+recordExt))
+	if len(files) != 1 {
+		t.Fatalf("got %d log files, want 1", len(files))
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var header recordHeader
+	err = json.NewDecoder(f).Decode(&header)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Format != recordFormat || header.Version != 2 || header.Backend != "iw" {
+		t.Errorf("header = %+v", header)
+	}
+
+	replay, err := NewReplayBackend(dir, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv, ok := replay.(Surveyor)
+	if !ok {
+		t.Fatal("replay of a log with surveys is not a Surveyor")
+	}
+	for i := range want {
+		networks, err := replay.Scan("wlan0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(networks) != len(want[i]) {
+			t.Fatalf("scan %d: got %d networks, want %d", i, len(networks), len(want[i]))
+		}
+		for j, n := range networks {
+			w := want[i][j]
+			if n.BSSID != w.BSSID || n.SSID != w.SSID || n.Signal != w.Signal || n.Channel != w.Channel {
+				t.Errorf("scan %d network %d = %s %q %d ch%d, want %s %q %d ch%d", i, j,
+					n.BSSID, n.SSID, n.Signal, n.Channel, w.BSSID, w.SSID, w.Signal, w.Channel)
+			}
+		}
+		got, err := sv.Survey("wlan0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, surveys) {
+			t.Errorf("scan %d: surveys = %+v, want %+v", i, got, surveys)
+		}
+	}
+	if _, err := replay.Scan("wlan0"); !errors.Is(err, ErrReplayDone) {
+		t.Errorf("third scan: err = %v, want ErrReplayDone", err)
+	}
+}
@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ieCountry = 7
+
+// CountryInfo is a decoded Country element.
+type CountryInfo struct {
+	Code        string // ISO 3166-1 alpha-2, e.g. "DE"
+	Environment string // "Indoor", "Outdoor", "Indoor/Outdoor" or "Non-country"
+	Subbands    []Subband
+}
+
+// Subband is one first-channel/count/power triplet of a Country element.
+type Subband struct {
+	FirstChannel int
+	NumChannels  int
+	MaxPower     int // dBm
+}
+
+// countryEnvironments maps the third country string byte to its meaning.
+var countryEnvironments = map[byte]string{
+	' ': "Indoor/Outdoor",
+	'I': "Indoor",
+	'O': "Outdoor",
+	'X': "Non-country",
+}
+
+// decodeCountry parses a Country element body: a 3-byte country string
+// followed by subband triplets. Operating extension triplets (first byte
+// >= 201) are skipped.
+func decodeCountry(data []byte) *CountryInfo {
+	if len(data) < 3 {
+		return nil
+	}
+	c := &CountryInfo{Code: string(data[:2]), Environment: countryEnvironments[data[2]]}
+	if c.Environment == "" {
+		c.Environment = "Indoor/Outdoor"
+	}
+	for t := data[3:]; len(t) >= 3; t = t[3:] {
+		if t[0] >= 201 {
+			continue
+		}
+		c.Subbands = append(c.Subbands, Subband{
+			FirstChannel: int(t[0]),
+			NumChannels:  int(t[1]),
+			MaxPower:     int(int8(t[2])),
+		})
+	}
+	return c
+}
+
+var (
+	iwCountryRe = regexp.MustCompile(`(?m)^\s*Country:\s*(\S+)\s+Environment:\s*(.+)$`)
+	iwSubbandRe = regexp.MustCompile(`Channels \[(\d+) - (\d+)\] @ (-?\d+) dBm`)
+)
+
+// parseCountry reads the "Country" section iw prints:
+//
+//	Country: DE	Environment: Indoor/Outdoor
+//		Channels [1 - 13] @ 20 dBm
+func parseCountry(block string) *CountryInfo {
+	m := iwCountryRe.FindStringSubmatch(block)
+	if m == nil {
+		return nil
+	}
+	c := &CountryInfo{Code: m[1], Environment: strings.TrimSpace(m[2])}
+	if c.Environment == "Indoor only" {
+		c.Environment = "Indoor"
+	} else if c.Environment == "Outdoor only" {
+		c.Environment = "Outdoor"
+	}
+
+	lines, _ := iwSection(block, "Country")
+	for _, l := range lines {
+		if s := iwSubbandRe.FindStringSubmatch(l); s != nil {
+			first, _ := strconv.Atoi(s[1])
+			last, _ := strconv.Atoi(s[2])
+			power, _ := strconv.Atoi(s[3])
+			step := 1
+			if first > 14 {
+				step = 4 // 5 GHz subbands are listed in 20 MHz channel steps
+			}
+			c.Subbands = append(c.Subbands, Subband{FirstChannel: first, NumChannels: (last-first)/step + 1, MaxPower: power})
+		}
+	}
+	return c
+}
+
+// RegDomain is the local regulatory domain as reported by the kernel.
+type RegDomain struct {
+	Country   string // "00" is the world domain
+	DFSRegion string // "DFS-FCC", "DFS-ETSI", "DFS-JP" or ""
+	Rules     []RegRule
+}
+
+// RegRule is one frequency range the regulatory domain permits.
+type RegRule struct {
+	StartFreq    int // MHz
+	EndFreq      int // MHz
+	MaxBandwidth int // MHz
+	MaxEIRP      int // dBm
+	Flags        []string
+}
+
+// Has reports whether the rule carries a flag such as "DFS" or "NO-OUTDOOR".
+func (r RegRule) Has(flag string) bool {
+	for _, f := range r.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule returns the rule that covers a 20 MHz channel centered on freq.
+func (d *RegDomain) Rule(freq int) (RegRule, bool) {
+	for _, r := range d.Rules {
+		if freq-10 >= r.StartFreq && freq+10 <= r.EndFreq {
+			return r, true
+		}
+	}
+	return RegRule{}, false
+}
+
+// Permits reports whether a 20 MHz channel centered on freq may be used.
+func (d *RegDomain) Permits(freq int) bool {
+	_, ok := d.Rule(freq)
+	return ok
+}
+
+var (
+	iwRegCountryRe = regexp.MustCompile(`^country (\S+?):\s*(\S*)`)
+	iwRegRuleRe    = regexp.MustCompile(`^\((\d+) - (\d+) @ (\d+)\), \(([^,]+), ([\d.]+|N/A)\)(?:, \([^)]*\))?(.*)$`)
+)
+
+// parseRegGet parses `iw reg get`, returning the global domain. The
+// per-phy domains of self-managed drivers that follow are ignored.
+func parseRegGet(output string) (*RegDomain, error) {
+	var d *RegDomain
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if m := iwRegCountryRe.FindStringSubmatch(line); m != nil {
+			if d != nil {
+				break
+			}
+			d = &RegDomain{Country: m[1], DFSRegion: m[2]}
+			if d.DFSRegion == "DFS-UNSET" {
+				d.DFSRegion = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "phy#") && d != nil {
+			break
+		}
+		m := iwRegRuleRe.FindStringSubmatch(line)
+		if m == nil || d == nil {
+			continue
+		}
+		r := RegRule{}
+		r.StartFreq, _ = strconv.Atoi(m[1])
+		r.EndFreq, _ = strconv.Atoi(m[2])
+		r.MaxBandwidth, _ = strconv.Atoi(m[3])
+		eirp, _ := strconv.ParseFloat(m[5], 64)
+		r.MaxEIRP = int(eirp)
+		for _, f := range strings.Split(m[6], ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				r.Flags = append(r.Flags, f)
+			}
+		}
+		d.Rules = append(d.Rules, r)
+	}
+	if d == nil {
+		return nil, fmt.Errorf("no country in 'iw reg get' output")
+	}
+	return d, nil
+}
+
+// CheckRegulatory flags networks that look misconfigured: those advertising
+// a different country than most others, and, when the local domain is
+// known, those on channels it does not permit. The result maps BSSID to
+// human-readable reasons.
+func CheckRegulatory(networks []Network, reg *RegDomain) map[string][]string {
+	issues := make(map[string][]string)
+
+	counts := make(map[string]int)
+	for _, n := range networks {
+		if n.Country != nil {
+			counts[n.Country.Code]++
+		}
+	}
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if counts[codes[i]] != counts[codes[j]] {
+			return counts[codes[i]] > counts[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+
+	// Only flag outliers when one country clearly leads
+	var majority string
+	if len(codes) > 1 && counts[codes[0]] > counts[codes[1]] {
+		majority = codes[0]
+	}
+
+	for _, n := range networks {
+		if majority != "" && n.Country != nil && n.Country.Code != majority {
+			issues[n.BSSID] = append(issues[n.BSSID],
+				fmt.Sprintf("advertises country %s, most APs say %s", n.Country.Code, majority))
+		}
+		// The world domain ("00") is a conservative fallback, not a country
+		if reg != nil && reg.Country != "00" && n.Frequency != 0 && !reg.Permits(n.Frequency) {
+			issues[n.BSSID] = append(issues[n.BSSID],
+				fmt.Sprintf("channel %d (%d MHz) not permitted in %s", n.Channel, n.Frequency, reg.Country))
+		}
+	}
+	return issues
+}
@@ -0,0 +1,279 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrReplayDone is returned by a replay backend once every capture has been
+// played back.
+var ErrReplayDone = errors.New("replay finished")
+
+// captureTimeLayouts are the fixed-width filename timestamp formats
+// recognised for replay captures, e.g. "20240131T142500.txt". Anything may
+// follow the timestamp. Full RFC 3339 stems such as "2024-05-01T10:00:00Z"
+// are accepted too.
+var captureTimeLayouts = []string{
+	"20060102T150405",
+	"20060102-150405",
+	"2006-01-02T15-04-05",
+}
+
+// capture is one recorded scan waiting to be replayed.
+type capture struct {
+	Time     time.Time
+	Backend  string // backend that produced Raw; empty for plain iw dumps
+	Raw      []byte
+	Networks []Network // parsed results, used when Raw cannot be re-parsed
+	Surveys  []ChannelSurvey
+}
+
+// networks re-parses the capture's raw output with the producing backend's
+// parser, so parser fixes apply to old captures. Output that has no
+// standalone parser falls back to the recorded results.
+func (c capture) networks() ([]Network, error) {
+	switch c.Backend {
+	case "", "iw":
+		return parseScanOutput(string(c.Raw)), nil
+	case "nl80211":
+		return parseNetlinkScanDump(c.Raw)
+	case "nmcli":
+		return parseNmcliOutput(string(c.Raw)), nil
+	default:
+		return append([]Network(nil), c.Networks...), nil
+	}
+}
+
+// replayBackend plays recorded scans back through the parser, pacing
+// captures by their original timestamps divided by speed. It reads both
+// plain 'iw scan' text dumps and log files written by the recorder.
+type replayBackend struct {
+	name     string
+	speed    float64
+	captures []capture
+
+	mu    sync.Mutex
+	next  int
+	start time.Time
+}
+
+// surveyingReplay is a replayBackend whose captures carry channel surveys.
+type surveyingReplay struct {
+	*replayBackend
+}
+
+// NewReplayBackend loads the capture file, or directory of timestamped
+// capture files and recorder logs, at path. A speed of 2 plays back twice as fast as recorded.
+func NewReplayBackend(path string, speed float64) (Backend, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be positive, got %g", speed)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("replay: %w", err)
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && !strings.HasPrefix(e.Name(), ".") {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	} else {
+		files = []string{path}
+	}
+
+	var captures []capture
+	for _, f := range files {
+		var cs []capture
+		if filepath.Ext(f) == recordExt {
+			cs, err = loadRecordLog(f)
+		} else {
+			cs, err = loadCapture(f)
+		}
+		if err != nil {
+			return nil, err
+		}
+		captures = append(captures, cs...)
+	}
+	if len(captures) == 0 {
+		return nil, fmt.Errorf("replay: no captures in %s", path)
+	}
+
+	sort.SliceStable(captures, func(i, j int) bool {
+		return captures[i].Time.Before(captures[j].Time)
+	})
+
+	b := &replayBackend{
+		name:     filepath.Base(path),
+		speed:    speed,
+		captures: captures,
+	}
+	// Only offer surveys when the recording has some
+	for _, c := range captures {
+		if c.Surveys != nil {
+			return surveyingReplay{b}, nil
+		}
+	}
+	return b, nil
+}
+
+// loadCapture reads one text capture, taking its time from the filename
+// when it carries a timestamp and from the modification time otherwise.
+func loadCapture(path string) ([]capture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+
+	c := capture{Time: info.ModTime(), Raw: data}
+	if t, ok := captureTime(filepath.Base(path)); ok {
+		c.Time = t
+	}
+	return []capture{c}, nil
+}
+
+// loadRecordLog reads every successful scan from a recorder log file.
+func loadRecordLog(path string) ([]capture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	var header recordHeader
+	if err := dec.Decode(&header); err != nil || header.Format != recordFormat {
+		return nil, fmt.Errorf("replay: %s is not a scan record log", path)
+	}
+	if header.Version > recordVersion {
+		return nil, fmt.Errorf("replay: %s has record version %d, newest supported is %d",
+			path, header.Version, recordVersion)
+	}
+
+	var captures []capture
+	last := make(map[string]int) // latest capture index per interface
+	for {
+		var rec scanRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("replay: %s: %w", path, err)
+		}
+		if rec.Kind == recordKindSurvey {
+			// Surveys follow the scan of the same interface
+			if i, ok := last[rec.Interface]; ok {
+				captures[i].Surveys = rec.Surveys
+			}
+			continue
+		}
+		if rec.Error != "" {
+			continue
+		}
+		last[rec.Interface] = len(captures)
+		captures = append(captures, capture{
+			Time:     rec.Time,
+			Backend:  rec.Backend,
+			Raw:      rec.rawBytes(),
+			Networks: rec.Networks,
+		})
+	}
+	return captures, nil
+}
+
+// captureTime extracts a timestamp from a capture filename.
+func captureTime(name string) (time.Time, bool) {
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+	// RFC 3339 varies in length with the zone and fractional seconds
+	if t, err := time.Parse(time.RFC3339, stem); err == nil {
+		return t, true
+	}
+	for _, layout := range captureTimeLayouts {
+		if len(stem) < len(layout) {
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, stem[:len(layout)], time.Local); err == nil {
+			return t, true
+		}
+	}
+	if secs, err := strconv.ParseInt(stem, 10, 64); err == nil {
+		return time.Unix(secs, 0), true
+	}
+	return time.Time{}, false
+}
+
+func (b *replayBackend) Name() string { return "replay" }
+
+func (b *replayBackend) Capabilities() Capabilities {
+	return CapPaced
+}
+
+// Interfaces reports the replay source as the only interface.
+func (b *replayBackend) Interfaces() ([]string, error) {
+	return []string{b.name}, nil
+}
+
+// Scan blocks until the next capture is due, then returns its parsed
+// networks stamped with the time they were originally seen.
+func (b *replayBackend) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+// ScanRaw is Scan that also returns the capture's raw output.
+func (b *replayBackend) ScanRaw(iface string) ([]byte, []Network, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.next >= len(b.captures) {
+		return nil, nil, ErrReplayDone
+	}
+	c := b.captures[b.next]
+
+	if b.next == 0 {
+		b.start = time.Now()
+	} else {
+		offset := c.Time.Sub(b.captures[0].Time)
+		due := b.start.Add(time.Duration(float64(offset) / b.speed))
+		time.Sleep(time.Until(due))
+	}
+	b.next++
+
+	networks, err := c.networks()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range networks {
+		networks[i].LastSeen = c.Time
+	}
+	return c.Raw, networks, nil
+}
+
+// Survey returns the surveys recorded with the capture last played.
+func (b surveyingReplay) Survey(iface string) ([]ChannelSurvey, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.next == 0 {
+		return nil, nil
+	}
+	return b.captures[b.next-1].Surveys, nil
+}
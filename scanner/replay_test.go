@@ -0,0 +1,36 @@
+package scanner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCaptureTime(t *testing.T) {
+	local := time.Date(2024, 1, 31, 14, 25, 0, 0, time.Local)
+	tests := []struct {
+		name string
+		want time.Time
+		ok   bool
+	}{
+		{"20240131T142500.txt", local, true},
+		{"20240131T142500-wlan0.txt", local, true},
+		{"20240131-142500.txt", local, true},
+		{"20240131-142500", local, true},
+		{"2024-01-31T14-25-00.log", local, true},
+		{"2024-05-01T10:00:00Z.txt", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"2024-05-01T10:00:00Z", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"2024-05-01T12:00:00+02:00.txt", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), true},
+		{"1706711100.txt", time.Unix(1706711100, 0), true},
+		{"scan.txt", time.Time{}, false},
+		{"2024-05-01.txt", time.Time{}, false},
+		{"20241331T142500.txt", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := captureTime(tt.name)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("captureTime(%q) = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	ieMobilityDomain  = 54
+	ieRRMEnabled      = 70
+	ieExtCapabilities = 127
+
+	mdFTOverDS          = 0x01 // FT capability and policy: FT over the DS
+	rrmNeighborReport   = 1    // bit in the first RRM capability octet
+	extCapBSSTransition = 19
+)
+
+// RoamingInfo is what a BSS advertises to help clients roam: 802.11r fast
+// transition, 802.11k radio measurement and 802.11v BSS transition
+// management.
+type RoamingInfo struct {
+	MobilityDomain bool   // Mobility Domain element present (802.11r)
+	MDID           uint16 // mobility domain identifier
+	FTOverDS       bool   // FT may run through the current AP
+	FTAKM          bool   // RSN lists an FT AKM suite
+	RRM            bool   // RRM Enabled Capabilities present (802.11k)
+	NeighborReport bool   // AP answers neighbor report requests
+	BSSTransition  bool   // BSS Transition extended capability (802.11v)
+}
+
+// FastTransition reports 802.11r support: a Mobility Domain element or an
+// FT AKM.
+func (r RoamingInfo) FastTransition() bool {
+	return r.MobilityDomain || r.FTAKM
+}
+
+// Badges lists the supported amendments as "k", "v" and "r".
+func (r RoamingInfo) Badges() []string {
+	var badges []string
+	if r.RRM {
+		badges = append(badges, "k")
+	}
+	if r.BSSTransition {
+		badges = append(badges, "v")
+	}
+	if r.FastTransition() {
+		badges = append(badges, "r")
+	}
+	return badges
+}
+
+// hasFTAKM reports whether any AKM is a fast transition suite.
+func hasFTAKM(si SecurityInfo) bool {
+	for _, akm := range si.AKMs {
+		if strings.HasPrefix(akm, "FT-") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeMobilityDomain parses a Mobility Domain element body: the MDID
+// followed by the FT capability and policy octet.
+func (r *RoamingInfo) decodeMobilityDomain(data []byte) {
+	if len(data) < 3 {
+		return
+	}
+	r.MobilityDomain = true
+	r.MDID = binary.LittleEndian.Uint16(data)
+	r.FTOverDS = data[2]&mdFTOverDS != 0
+}
+
+// decodeRRM parses an RRM Enabled Capabilities element body.
+func (r *RoamingInfo) decodeRRM(data []byte) {
+	if len(data) < 1 {
+		return
+	}
+	r.RRM = true
+	r.NeighborReport = data[0]&(1<<rrmNeighborReport) != 0
+}
+
+// extCapBit reports whether bit n of an Extended Capabilities element body
+// is set. The element may be shorter than the highest defined bit.
+func extCapBit(data []byte, n int) bool {
+	return n/8 < len(data) && data[n/8]&(1<<(n%8)) != 0
+}
+
+var iwMobilityDomainRe = regexp.MustCompile(`(?m)^\s*MD: MDID: 0x([0-9a-fA-F]{4})(.*)$`)
+
+// parseRoaming reads the roaming elements iw prints:
+//
+//	MD: MDID: 0x1234 FT-over-DS
+//	RM enabled capabilities:
+//		Capabilities: 0x73 0x00 0x00 0x00 0x00
+//			Neighbor Report
+//	Extended capabilities:
+//		 * BSS Transition
+func parseRoaming(block string, si SecurityInfo) RoamingInfo {
+	r := RoamingInfo{FTAKM: hasFTAKM(si)}
+	if m := iwMobilityDomainRe.FindStringSubmatch(block); m != nil {
+		mdid, _ := strconv.ParseUint(m[1], 16, 16)
+		r.MobilityDomain = true
+		r.MDID = uint16(mdid)
+		r.FTOverDS = strings.Contains(m[2], "FT-over-DS")
+	}
+	if lines, ok := iwSection(block, "RM enabled capabilities"); ok {
+		r.RRM = true
+		for _, l := range lines {
+			if l == "Neighbor Report" {
+				r.NeighborReport = true
+			}
+		}
+	}
+	if lines, ok := iwSection(block, "Extended capabilities"); ok {
+		for _, l := range lines {
+			if l == "BSS Transition" {
+				r.BSSTransition = true
+			}
+		}
+	}
+	return r
+}
+
+// CheckRoaming flags BSSIDs of a multi-AP SSID that do not advertise the
+// same roaming features as their siblings, which leaves clients roaming
+// slowly or not at all between them. The result maps BSSID to
+// human-readable reasons. Hidden networks are skipped.
+func CheckRoaming(networks []Network) map[string][]string {
+	issues := make(map[string][]string)
+
+	bySSID := make(map[string][]Network)
+	for _, n := range networks {
+		if n.SSID != "" && n.SSID != "<hidden>" {
+			bySSID[n.SSID] = append(bySSID[n.SSID], n)
+		}
+	}
+
+	features := []struct {
+		name string
+		has  func(RoamingInfo) bool
+	}{
+		{"802.11k", func(r RoamingInfo) bool { return r.RRM }},
+		{"802.11v", func(r RoamingInfo) bool { return r.BSSTransition }},
+		{"802.11r", RoamingInfo.FastTransition},
+	}
+
+	for _, group := range bySSID {
+		if len(group) < 2 {
+			continue
+		}
+		for _, f := range features {
+			count := 0
+			for _, n := range group {
+				if f.has(n.Roaming) {
+					count++
+				}
+			}
+			if count == 0 || count == len(group) {
+				continue
+			}
+			for _, n := range group {
+				if !f.has(n.Roaming) {
+					issues[n.BSSID] = append(issues[n.BSSID],
+						fmt.Sprintf("no %s, which %d of %d APs for this SSID advertise", f.name, count, len(group)))
+				}
+			}
+		}
+
+		// FT only works within one mobility domain
+		domains := make(map[uint16]bool)
+		for _, n := range group {
+			if n.Roaming.MobilityDomain {
+				domains[n.Roaming.MDID] = true
+			}
+		}
+		if len(domains) > 1 {
+			ids := make([]string, 0, len(domains))
+			for id := range domains {
+				ids = append(ids, fmt.Sprintf("0x%04x", id))
+			}
+			sort.Strings(ids)
+			for _, n := range group {
+				if n.Roaming.MobilityDomain {
+					issues[n.BSSID] = append(issues[n.BSSID],
+						fmt.Sprintf("mobility domain 0x%04x, APs for this SSID use %s", n.Roaming.MDID, strings.Join(ids, " ")))
+				}
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,101 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeRoamingElements(t *testing.T) {
+	ie := func(id byte, data ...byte) []byte { return append([]byte{id, byte(len(data))}, data...) }
+	ftPSK := ie(ieRSN, suiteBody(suite(ouiIEEE, 4), [][]byte{suite(ouiIEEE, 4)}, [][]byte{suite(ouiIEEE, 4)}, nil)...)
+
+	tests := []struct {
+		name string
+		ies  []byte
+		want RoamingInfo
+	}{
+		{"none", nil, RoamingInfo{}},
+		{"MDE over DS", ie(ieMobilityDomain, 0x34, 0x12, 0x01), RoamingInfo{MobilityDomain: true, MDID: 0x1234, FTOverDS: true}},
+		{"MDE over air", ie(ieMobilityDomain, 0xcd, 0xab, 0x00), RoamingInfo{MobilityDomain: true, MDID: 0xabcd}},
+		{"MDE truncated", ie(ieMobilityDomain, 0x34, 0x12), RoamingInfo{}},
+		{"FT AKM only", ftPSK, RoamingInfo{FTAKM: true}},
+		{"RRM neighbor report", ie(ieRRMEnabled, 0x73, 0, 0, 0, 0), RoamingInfo{RRM: true, NeighborReport: true}},
+		{"RRM without neighbor report", ie(ieRRMEnabled, 0x71, 0, 0, 0, 0), RoamingInfo{RRM: true}},
+		{"RRM empty", ie(ieRRMEnabled), RoamingInfo{}},
+		{"BSS transition", ie(ieExtCapabilities, 0x04, 0x00, 0x08, 0x00), RoamingInfo{BSSTransition: true}},
+		{"ExtCap without BSS transition", ie(ieExtCapabilities, 0x04, 0x00, 0xf7), RoamingInfo{}},
+		{"ExtCap too short", ie(ieExtCapabilities, 0x04, 0x00), RoamingInfo{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n Network
+			applyIEs(&n, tt.ies, 0)
+			if n.Roaming != tt.want {
+				t.Errorf("roaming = %+v, want %+v", n.Roaming, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtCapBit(t *testing.T) {
+	data := []byte{0x01, 0x80, 0x08}
+	tests := []struct {
+		bit  int
+		want bool
+	}{
+		{0, true},
+		{1, false},
+		{15, true},
+		{19, true},
+		{20, false},
+		{24, false}, // past the end
+		{70, false},
+	}
+	for _, tt := range tests {
+		if got := extCapBit(data, tt.bit); got != tt.want {
+			t.Errorf("extCapBit(%d) = %v, want %v", tt.bit, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoaming(t *testing.T) {
+	block := `BSS a4:2b:8c:01:02:03(on wlan0)
+	SSID: HomeNet
+	MD: MDID: 0x1234 FT-over-DS
+	RM enabled capabilities:
+		Capabilities: 0x73 0x00 0x00 0x00 0x00
+			Link Measurement
+			Neighbor Report
+			Beacon Passive Measurement
+			Beacon Active Measurement
+			Beacon Table Measurement
+		Nonoperating Channel Max Measurement Duration: 0
+		Measurement Pilot Capability: 0
+	Extended capabilities:
+		 * Extended Channel Switching
+		 * BSS Transition
+		 * Operating Mode Notification
+`
+	want := RoamingInfo{MobilityDomain: true, MDID: 0x1234, FTOverDS: true, FTAKM: true, RRM: true, NeighborReport: true, BSSTransition: true}
+	if got := parseRoaming(block, SecurityInfo{AKMs: []string{"FT-SAE"}}); got != want {
+		t.Errorf("parseRoaming() = %+v, want %+v", got, want)
+	}
+	if got := want.Badges(); !reflect.DeepEqual(got, []string{"k", "v", "r"}) {
+		t.Errorf("Badges() = %q, want [k v r]", got)
+	}
+
+	bare := `BSS a4:2b:8c:01:02:04(on wlan0)
+	SSID: HomeNet
+	MD: MDID: 0x1234
+	RM enabled capabilities:
+		Capabilities: 0x71 0x00 0x00 0x00 0x00
+			Link Measurement
+			Beacon Passive Measurement
+	Extended capabilities:
+		 * Extended Channel Switching
+`
+	want = RoamingInfo{MobilityDomain: true, MDID: 0x1234, RRM: true}
+	if got := parseRoaming(bare, SecurityInfo{AKMs: []string{"PSK"}}); got != want {
+		t.Errorf("parseRoaming() = %+v, want %+v", got, want)
+	}
+}
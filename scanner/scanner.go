@@ -1,92 +1,222 @@
 package scanner
 
 import (
+	"errors"
 	"fmt"
-	"math/rand"
-	"os/exec"
 	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
-)
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
+	"wifiscanner/scanner/channel"
+)
 
 // Network represents a discovered WiFi network.
 type Network struct {
-	BSSID     string
-	SSID      string
+	BSSID        string
+	SSID         string
+	Signal       int // dBm, strongest across Readings
+	Noise        int // dBm noise floor from the channel survey, 0 if unknown
+	Frequency    int // MHz
+	Channel      int
+	Security     string       // WPA3-ENT, WPA2-ENT, WPA3, WPA2, WEP, OPEN, ... — see SecurityInfo.Class
+	SecurityInfo SecurityInfo // decoded RSN/WPA elements
+	PHY          PHYInfo      // HT/VHT/HE/EHT capabilities and operating width
+	Roaming      RoamingInfo  // 802.11k/v/r support
+	LastSeen     time.Time
+	Readings     []Reading // per-interface signal, sorted by interface
+
+	OWETransition *OWETransition // set when the BSS advertises an OWE transition partner
+	BSSLoad       *BSSLoad       // AP-reported load, nil if not advertised
+	Country       *CountryInfo   // Country element, nil if not advertised
+	WPS           *WPSInfo       // Wi-Fi Protected Setup element, nil if not advertised
+	Attributes    []Attribute    // decoded Extended Capabilities and vendor elements
+	APName        string         // hostname advertised by Cisco or Aruba APs
+	IEs           []byte         // raw information elements, nil unless the backend has CapRawIEs
+	TSF           uint64         // AP timing synchronisation function, µs since the BSS started; 0 if unknown
+}
+
+// Reading is the signal one interface measured for a network.
+type Reading struct {
+	Interface string
 	Signal    int // dBm
-	Frequency int // MHz
-	Channel   int
-	Security  string // WPA3, WPA2, WPA2/WPA, WPA, WEP, OPEN
-	LastSeen  time.Time
 }
 
-// Scanner handles WiFi network discovery.
+// Scanner handles WiFi network discovery by delegating to a Backend,
+// scanning every configured interface in parallel.
 type Scanner struct {
-	Interface string
-	Demo      bool
+	Interfaces []string
+	Backend    Backend
+
+	mu      sync.Mutex
+	surveys []ChannelSurvey // from the last scan, if the backend is a Surveyor
 }
 
-// New creates a Scanner, auto-detecting the wireless interface if not specified.
-func New(iface string, demo bool) (*Scanner, error) {
+// New creates a Scanner, auto-detecting the wireless interface if none is given.
+func New(ifaces []string, backend Backend) (*Scanner, error) {
 	s := &Scanner{
-		Interface: iface,
-		Demo:      demo,
-	}
-
-	if demo {
-		s.Interface = "wlan0"
-		return s, nil
+		Interfaces: ifaces,
+		Backend:    backend,
 	}
 
-	if iface == "" {
-		detected, err := detectInterface()
+	if len(ifaces) == 0 {
+		detected, err := DetectInterfaces(backend)
 		if err != nil {
 			return nil, fmt.Errorf("no wireless interface found: %w", err)
 		}
-		s.Interface = detected
+		s.Interfaces = detected[:1]
 	}
 
 	return s, nil
 }
 
-// detectInterface finds the first wireless interface via iw.
-func detectInterface() (string, error) {
-	out, err := exec.Command("iw", "dev").Output()
+// DetectInterfaces returns every interface the backend can scan on.
+func DetectInterfaces(backend Backend) ([]string, error) {
+	lister, ok := backend.(InterfaceLister)
+	if !ok {
+		return nil, fmt.Errorf("backend %q cannot detect interfaces, use -i", backend.Name())
+	}
+	ifaces, err := lister.Interfaces()
 	if err != nil {
-		return "", fmt.Errorf("failed to run 'iw dev': %w", err)
+		return nil, err
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("backend %q reported no interfaces", backend.Name())
 	}
+	return ifaces, nil
+}
 
-	re := regexp.MustCompile(`Interface\s+(\S+)`)
-	matches := re.FindStringSubmatch(string(out))
-	if len(matches) < 2 {
-		return "", fmt.Errorf("no wireless interface detected in 'iw dev' output")
+// Surveys returns the channel surveys collected by the last Scan, sorted by
+// frequency. It is empty when the backend cannot survey.
+func (s *Scanner) Surveys() []ChannelSurvey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.surveys
+}
+
+// Regulatory returns the local regulatory domain, if the backend can
+// report it.
+func (s *Scanner) Regulatory() (*RegDomain, error) {
+	rr, ok := s.Backend.(RegulatoryReporter)
+	if !ok {
+		return nil, fmt.Errorf("backend %q cannot report the regulatory domain", s.Backend.Name())
 	}
-	return matches[1], nil
+	return rr.Regulatory()
 }
 
-// Scan performs a WiFi scan and returns discovered networks.
+// Scan scans all interfaces concurrently and merges the results per BSSID.
+// If only some interfaces fail, the merged results from the rest are
+// returned together with an error describing the failures.
 func (s *Scanner) Scan() ([]Network, error) {
-	if s.Demo {
-		return s.mockScan(), nil
+	type result struct {
+		networks []Network
+		surveys  []ChannelSurvey
+		err      error
+	}
+	results := make([]result, len(s.Interfaces))
+
+	var wg sync.WaitGroup
+	for i, iface := range s.Interfaces {
+		wg.Add(1)
+		go func(i int, iface string) {
+			defer wg.Done()
+			networks, err := s.Backend.Scan(iface)
+			if err != nil && len(s.Interfaces) > 1 {
+				err = fmt.Errorf("%s: %w", iface, err)
+			}
+
+			// Survey data is best effort: a scan without it is still useful
+			var surveys []ChannelSurvey
+			if sv, ok := s.Backend.(Surveyor); ok && err == nil {
+				if surveys, _ = sv.Survey(iface); surveys != nil {
+					applyNoise(networks, surveys)
+				}
+			}
+			results[i] = result{networks, surveys, err}
+		}(i, iface)
+	}
+	wg.Wait()
+
+	var errs []string
+	var firstErr error
+	perIface := make(map[string][]Network)
+	var surveys []ChannelSurvey
+	for i, r := range results {
+		surveys = append(surveys, r.surveys...)
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			errs = append(errs, r.err.Error())
+			continue
+		}
+		perIface[s.Interfaces[i]] = r.networks
 	}
 
-	// Try active scan first, fall back to cached results
-	out, err := exec.Command("iw", "dev", s.Interface, "scan").CombinedOutput()
-	if err != nil {
-		out, err = exec.Command("iw", "dev", s.Interface, "scan", "dump").CombinedOutput()
-		if err != nil {
-			return nil, fmt.Errorf("scan failed: %w\n%s", err, string(out))
+	sortSurveys(surveys)
+	s.mu.Lock()
+	s.surveys = surveys
+	s.mu.Unlock()
+
+	if len(errs) == len(results) {
+		return nil, firstErr
+	}
+
+	merged := mergeNetworks(perIface)
+	switch len(errs) {
+	case 0:
+		return merged, nil
+	case 1:
+		return merged, firstErr
+	default:
+		return merged, errors.New(strings.Join(errs, "; "))
+	}
+}
+
+// mergeNetworks combines per-interface results into one entry per BSSID,
+// keeping the strongest interface's view and every interface's reading.
+func mergeNetworks(perIface map[string][]Network) []Network {
+	ifaces := make([]string, 0, len(perIface))
+	for iface := range perIface {
+		ifaces = append(ifaces, iface)
+	}
+	sort.Strings(ifaces)
+
+	var merged []Network
+	index := make(map[string]int)
+
+	for _, iface := range ifaces {
+		for _, n := range perIface[iface] {
+			reading := Reading{Interface: iface, Signal: n.Signal}
+
+			i, seen := index[n.BSSID]
+			if !seen {
+				n.Readings = []Reading{reading}
+				index[n.BSSID] = len(merged)
+				merged = append(merged, n)
+				continue
+			}
+
+			m := &merged[i]
+			readings := append(m.Readings, reading)
+			lastSeen := m.LastSeen
+			if n.Signal > m.Signal {
+				*m = n
+			}
+			if lastSeen.After(m.LastSeen) {
+				m.LastSeen = lastSeen
+			}
+			m.Readings = readings
 		}
 	}
 
-	networks := parseScanOutput(string(out))
-	return networks, nil
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Signal > merged[j].Signal
+	})
+
+	return merged
 }
 
 // parseScanOutput parses iw scan output into Network structs.
@@ -107,7 +237,7 @@ func parseScanOutput(output string) []Network {
 		if m := regexp.MustCompile(`^([0-9a-fA-F:]{17})`).FindStringSubmatch(block); len(m) > 1 {
 			n.BSSID = strings.ToUpper(m[1])
 		} else {
-			continue // Not a real BSS block (e.g. "BSS Load:" split artifact)
+			continue // Not a BSS block (e.g. text before the first "BSS" line)
 		}
 
 		// SSID
@@ -123,20 +253,33 @@ func parseScanOutput(output string) []Network {
 			n.Signal, _ = strconv.Atoi(m[1])
 		}
 
-		// Frequency
-		if m := regexp.MustCompile(`freq:\s*(\d+)`).FindStringSubmatch(block); len(m) > 1 {
-			n.Frequency, _ = strconv.Atoi(m[1])
+		// Frequency; newer iw prints a fraction, which sub-1 GHz channels need
+		khz := 0
+		if m := regexp.MustCompile(`freq:\s*(\d+(?:\.\d+)?)`).FindStringSubmatch(block); len(m) > 1 {
+			mhz, _ := strconv.ParseFloat(m[1], 64)
+			khz = int(mhz*1000 + 0.5)
+			n.Frequency = khz / 1000
 		}
 
 		// Channel — prefer DS Parameter set, fall back to frequency calculation
 		if m := regexp.MustCompile(`DS Parameter set: channel (\d+)`).FindStringSubmatch(block); len(m) > 1 {
 			n.Channel, _ = strconv.Atoi(m[1])
 		} else {
-			n.Channel = freqToChannel(n.Frequency)
+			n.Channel = channel.NumberKHz(khz)
 		}
 
 		// Security
-		n.Security = parseSecurity(block)
+		n.SecurityInfo = parseSecurity(block)
+		n.Security = n.SecurityInfo.Class()
+		n.OWETransition = parseOWETransition(block)
+		n.PHY = parsePHY(block)
+		n.BSSLoad = parseBSSLoad(block)
+		n.Country = parseCountry(block)
+		n.WPS = parseWPS(block)
+		n.Roaming = parseRoaming(block, n.SecurityInfo)
+		n.Attributes = parseAttributes(block)
+		n.APName = apName(n.Attributes)
+		n.TSF = parseTSF(block)
 
 		networks = append(networks, n)
 	}
@@ -148,103 +291,51 @@ func parseScanOutput(output string) []Network {
 	return networks
 }
 
-func parseSecurity(block string) string {
-	hasRSN := strings.Contains(block, "RSN:")
-	hasWPA := strings.Contains(block, "WPA:")
-	hasSAE := strings.Contains(block, "SAE")
-
-	switch {
-	case hasSAE:
-		return "WPA3"
-	case hasRSN && hasWPA:
-		return "WPA2/WPA"
-	case hasRSN:
-		return "WPA2"
-	case hasWPA:
-		return "WPA"
-	case strings.Contains(block, "Privacy"):
-		return "WEP"
-	default:
-		return "OPEN"
+var (
+	iwCapabilityRe = regexp.MustCompile(`(?m)^\s*capability:\s*(.*)$`)
+	iwHexSuffixRe  = regexp.MustCompile(`\(0x([0-9a-fA-F]+)\)`)
+)
+
+// iwSection returns the body of the first "name:" section of an iw BSS
+// block: the text after the colon on the header line followed by every line
+// indented deeper than the header, trimmed of indentation and "* " bullets.
+func iwSection(block, name string) ([]string, bool) {
+	lines := strings.Split(block, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if !strings.HasPrefix(trimmed, name+":") {
+			continue
+		}
+		indent := len(line) - len(trimmed)
+
+		var body []string
+		if rest := trimIWLine(trimmed[len(name)+1:]); rest != "" {
+			body = append(body, rest)
+		}
+		for _, next := range lines[i+1:] {
+			t := strings.TrimLeft(next, " \t")
+			if t == "" || len(next)-len(t) <= indent {
+				break
+			}
+			body = append(body, trimIWLine(t))
+		}
+		return body, true
 	}
+	return nil, false
 }
 
-func freqToChannel(freq int) int {
-	switch {
-	case freq >= 2412 && freq <= 2472:
-		return (freq - 2407) / 5
-	case freq == 2484:
-		return 14
-	case freq >= 5180 && freq <= 5825:
-		return (freq - 5000) / 5
-	case freq >= 5955 && freq <= 7115:
-		return (freq - 5950) / 5
-	default:
-		return 0
-	}
+func trimIWLine(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimSpace(strings.TrimPrefix(s, "*"))
 }
 
-// mockScan generates realistic fake network data for demo/testing.
-func (s *Scanner) mockScan() []Network {
-	type mock struct {
-		ssid       string
-		bssid      string
-		security   string
-		baseSignal int
-		freq       int
-	}
-
-	mocks := []mock{
-		{"NETGEAR-5G-Home", "A4:2B:8C:D1:E5:F0", "WPA2", -35, 5180},
-		{"xfinitywifi", "B0:C7:45:3A:91:DE", "OPEN", -42, 2437},
-		{"FBI_Surveillance_Van_7", "C8:3A:35:FF:02:11", "WPA3", -48, 5240},
-		{"Pretty Fly for a WiFi", "D4:01:C3:7E:A8:55", "WPA2", -55, 2412},
-		{"The LAN Before Time", "10:68:3F:6B:33:C7", "WPA2", -58, 2462},
-		{"Bill Wi the Science Fi", "28:C6:8E:CE:47:9B", "WPA2/WPA", -63, 2427},
-		{"DROP TABLE *;--", "00:0E:8E:BE:EF:00", "WPA2", -65, 5300},
-		{"Skynet Global Defense", "00:09:0F:44:55:66", "WPA3", -68, 5500},
-		{"404 Network Unavail", "AC:67:06:DD:EE:01", "WPA2", -72, 2452},
-		{"wu-tang LAN", "34:A1:F7:8C:22:D0", "WPA2", -74, 2417},
-		{"<hidden>", "B4:FB:E4:BC:DE:F0", "WPA2", -76, 5220},
-		{"linksys", "78:A0:51:3E:C9:44", "WEP", -78, 2422},
-		{"DIRECT-roku-123", "9C:B2:E4:16:F8:73", "WPA2", -82, 2447},
-		{"HP-Print-A1-Officejet", "B0:5A:DA:01:23:45", "OPEN", -85, 2432},
-		{"oldrouter", "D0:E1:F2:03:14:25", "OPEN", -88, 2442},
-		{"TP-Link_Guest_5G", "50:C7:BF:15:26:37", "WPA2", -91, 5745},
-	}
-
-	networks := make([]Network, len(mocks))
-	now := time.Now()
-
-	for i, m := range mocks {
-		jitter := rand.Intn(7) - 3 // -3 to +3 dBm variation
-		networks[i] = Network{
-			BSSID:     m.bssid,
-			SSID:      m.ssid,
-			Signal:    m.baseSignal + jitter,
-			Frequency: m.freq,
-			Channel:   freqToChannel(m.freq),
-			Security:  m.security,
-			LastSeen:  now,
+// iwFields splits "Key: value" section lines into a map.
+func iwFields(lines []string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range lines {
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			fields[strings.TrimSpace(k)] = strings.TrimSpace(v)
 		}
 	}
-
-	// Occasional roaming network to exercise new-network alerts (~30% chance)
-	if rand.Intn(10) < 3 {
-		networks = append(networks, Network{
-			BSSID:     "A4:77:33:AB:CD:EF",
-			SSID:      "GoogleGuest-5G",
-			Signal:    -60 + rand.Intn(7) - 3,
-			Frequency: 5500,
-			Channel:   freqToChannel(5500),
-			Security:  "WPA2",
-			LastSeen:  now,
-		})
-	}
-
-	sort.Slice(networks, func(i, j int) bool {
-		return networks[i].Signal > networks[j].Signal
-	})
-
-	return networks
+	return fields
 }
@@ -0,0 +1,457 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RSN capability bits (IEEE 802.11-2020 9.4.2.24.4).
+const (
+	rsnCapPreAuth     = 0x0001
+	rsnCapMFPRequired = 0x0040
+	rsnCapMFPCapable  = 0x0080
+)
+
+// Suite selector OUIs.
+const (
+	ouiIEEE      = 0x000FAC
+	ouiMicrosoft = 0x0050F2
+)
+
+// SecurityInfo is the decoded RSN and/or legacy WPA element of a network.
+// Cipher and AKM names are normalised so every backend reports the same
+// strings, e.g. "CCMP" and "FT-SAE".
+type SecurityInfo struct {
+	RSN     bool // RSN element present (WPA2/WPA3)
+	WPA     bool // legacy WPA vendor element present
+	Privacy bool // Privacy capability bit (WEP when neither RSN nor WPA)
+
+	GroupCipher     string
+	PairwiseCiphers []string
+	AKMs            []string // PSK, SAE, 802.1X, FT-PSK, OWE, SuiteB-192, ...
+
+	PMFCapable      bool
+	PMFRequired     bool
+	RSNCapabilities uint16
+	HasCapabilities bool // RSNCapabilities was present in the element
+}
+
+// akmNames maps IEEE 00-0F-AC AKM suite types to normalised names.
+var akmNames = map[byte]string{
+	1:  "802.1X",
+	2:  "PSK",
+	3:  "FT-802.1X",
+	4:  "FT-PSK",
+	5:  "802.1X-SHA256",
+	6:  "PSK-SHA256",
+	7:  "TDLS",
+	8:  "SAE",
+	9:  "FT-SAE",
+	10: "AP-PeerKey",
+	11: "SuiteB",
+	12: "SuiteB-192",
+	13: "FT-802.1X-SHA384",
+	14: "FILS-SHA256",
+	15: "FILS-SHA384",
+	16: "FT-FILS-SHA256",
+	17: "FT-FILS-SHA384",
+	18: "OWE",
+	19: "FT-PSK-SHA384",
+	20: "PSK-SHA384",
+	24: "SAE-EXT-KEY",
+	25: "FT-SAE-EXT-KEY",
+}
+
+// cipherNames maps IEEE 00-0F-AC cipher suite types to the names iw prints.
+var cipherNames = map[byte]string{
+	0:  "GROUP",
+	1:  "WEP-40",
+	2:  "TKIP",
+	4:  "CCMP",
+	5:  "WEP-104",
+	6:  "AES-128-CMAC",
+	7:  "NO-GROUP",
+	8:  "GCMP-128",
+	9:  "GCMP-256",
+	10: "CCMP-256",
+	11: "AES-128-GMAC",
+	12: "AES-256-GMAC",
+	13: "AES-256-CMAC",
+}
+
+// iwAKMNames maps iw's AKM spellings to normalised names. Several contain
+// spaces, so they are matched longest-first rather than split on spaces.
+var iwAKMNames = map[string]string{
+	"IEEE 802.1X":             "802.1X",
+	"PSK":                     "PSK",
+	"FT/IEEE 802.1X":          "FT-802.1X",
+	"FT/PSK":                  "FT-PSK",
+	"IEEE 802.1X/SHA-256":     "802.1X-SHA256",
+	"PSK/SHA-256":             "PSK-SHA256",
+	"TDLS/TPK":                "TDLS",
+	"SAE":                     "SAE",
+	"FT/SAE":                  "FT-SAE",
+	"IEEE 802.1X/SUITE-B":     "SuiteB",
+	"IEEE 802.1X/SUITE-B-192": "SuiteB-192",
+	"FT/IEEE 802.1X/SHA-384":  "FT-802.1X-SHA384",
+	"FILS/SHA-256":            "FILS-SHA256",
+	"FILS/SHA-384":            "FILS-SHA384",
+	"FT/FILS/SHA-256":         "FT-FILS-SHA256",
+	"FT/FILS/SHA-384":         "FT-FILS-SHA384",
+	"OWE":                     "OWE",
+	"FT/PSK/SHA-384":          "FT-PSK-SHA384",
+	"PSK/SHA-384":             "PSK-SHA384",
+	"SAE-EXT-KEY":             "SAE-EXT-KEY",
+	"FT/SAE-EXT-KEY":          "FT-SAE-EXT-KEY",
+}
+
+// iwAKMOrder holds the iwAKMNames keys longest-first, the order
+// splitIWAKMs tries them in.
+var iwAKMOrder []string
+
+func init() {
+	for name := range iwAKMNames {
+		iwAKMOrder = append(iwAKMOrder, name)
+	}
+	sort.Slice(iwAKMOrder, func(i, j int) bool { return len(iwAKMOrder[i]) > len(iwAKMOrder[j]) })
+}
+
+// AuthMode is how clients authenticate to a network, used for filtering.
+type AuthMode string
+
+const (
+	AuthOpen     AuthMode = "OPEN"
+	AuthOWE      AuthMode = "OWE" // Enhanced Open
+	AuthWEP      AuthMode = "WEP"
+	AuthPSK      AuthMode = "PSK"
+	AuthPSKSAE   AuthMode = "PSK/SAE" // WPA2/WPA3 transition
+	AuthSAE      AuthMode = "SAE"
+	AuthEAP      AuthMode = "802.1X"
+	AuthEAPSuite AuthMode = "802.1X-192" // WPA3-Enterprise 192-bit
+)
+
+// AuthModes lists every AuthMode in display order.
+var AuthModes = []AuthMode{AuthOpen, AuthOWE, AuthWEP, AuthPSK, AuthPSKSAE, AuthSAE, AuthEAP, AuthEAPSuite}
+
+// AuthMode classifies the network's AKMs. Any 802.1X AKM makes it
+// enterprise, even when a PSK AKM is also offered.
+func (si SecurityInfo) AuthMode() AuthMode {
+	sae := si.hasAKM(isSAE)
+	psk := si.hasAKM(func(a string) bool { return akmFamily(a) == "PSK" && !isSAE(a) })
+	switch {
+	case si.hasAKM(func(a string) bool { return a == "SuiteB-192" }):
+		return AuthEAPSuite
+	case si.IsEnterprise():
+		return AuthEAP
+	case sae && psk:
+		return AuthPSKSAE
+	case sae:
+		return AuthSAE
+	case si.isOWE():
+		return AuthOWE
+	case si.RSN || si.WPA:
+		return AuthPSK
+	case si.Privacy:
+		return AuthWEP
+	default:
+		return AuthOpen
+	}
+}
+
+// IsEnterprise reports whether the network offers 802.1X authentication.
+func (si SecurityInfo) IsEnterprise() bool {
+	return si.hasAKM(func(a string) bool { return akmFamily(a) == "802.1X" })
+}
+
+// isWPA3Enterprise applies the WPA3-Enterprise only-mode rule: PMF required
+// and a SHA-256 or stronger 802.1X AKM.
+func (si SecurityInfo) isWPA3Enterprise() bool {
+	return si.PMFRequired && si.hasAKM(func(a string) bool {
+		return a == "802.1X-SHA256" || a == "FT-802.1X-SHA384" || strings.HasPrefix(a, "SuiteB")
+	})
+}
+
+// isOWE reports whether OWE is the only AKM offered.
+func (si SecurityInfo) isOWE() bool {
+	return len(si.AKMs) > 0 && !si.hasAKM(func(a string) bool { return a != "OWE" })
+}
+
+// Class returns the coarse security class used for colouring and sorting:
+// WPA3-ENT-192, WPA3-ENT, WPA2-ENT, WPA-ENT, WPA3, OWE, WPA2/WPA, WPA2, WPA,
+// WEP or OPEN. GroupOWETransition additionally labels merged pairs OWE-TM.
+func (si SecurityInfo) Class() string {
+	switch {
+	case si.AuthMode() == AuthEAPSuite:
+		return "WPA3-ENT-192"
+	case si.IsEnterprise() && si.isWPA3Enterprise():
+		return "WPA3-ENT"
+	case si.IsEnterprise() && si.RSN:
+		return "WPA2-ENT"
+	case si.IsEnterprise():
+		return "WPA-ENT"
+	case si.hasAKM(isSAE):
+		return "WPA3"
+	case si.isOWE():
+		return "OWE"
+	case si.RSN && si.WPA:
+		return "WPA2/WPA"
+	case si.RSN:
+		return "WPA2"
+	case si.WPA:
+		return "WPA"
+	case si.Privacy:
+		return "WEP"
+	default:
+		return "OPEN"
+	}
+}
+
+// Label returns a one-line summary such as
+// "WPA2/WPA3-Transition PSK CCMP PMF-opt".
+func (si SecurityInfo) Label() string {
+	parts := []string{si.protocol()}
+	if auth := si.authFamily(); auth != "" && auth != parts[0] {
+		parts = append(parts, auth)
+	}
+	if len(si.PairwiseCiphers) > 0 {
+		parts = append(parts, strings.Join(si.PairwiseCiphers, "+"))
+	}
+	switch {
+	case si.PMFRequired:
+		parts = append(parts, "PMF-req")
+	case si.PMFCapable:
+		parts = append(parts, "PMF-opt")
+	}
+	return strings.Join(parts, " ")
+}
+
+// protocol names the WPA generation(s) the network offers.
+func (si SecurityInfo) protocol() string {
+	switch si.Class() {
+	case "WPA3-ENT-192":
+		return "WPA3-Enterprise-192"
+	case "WPA3-ENT":
+		return "WPA3-Enterprise"
+	case "WPA2-ENT":
+		if si.WPA {
+			return "WPA2/WPA-Enterprise"
+		}
+		return "WPA2-Enterprise"
+	case "WPA-ENT":
+		return "WPA-Enterprise"
+	}
+
+	sae := si.hasAKM(isSAE)
+	legacy := si.hasAKM(func(a string) bool { return !isSAE(a) })
+	switch {
+	case sae && legacy:
+		return "WPA2/WPA3-Transition"
+	case sae:
+		return "WPA3"
+	}
+	return si.Class()
+}
+
+// authFamily groups the AKMs into PSK (including SAE), 802.1X and OWE.
+func (si SecurityInfo) authFamily() string {
+	var families []string
+	seen := make(map[string]bool)
+	for _, akm := range si.AKMs {
+		f := akmFamily(akm)
+		if !seen[f] {
+			seen[f] = true
+			families = append(families, f)
+		}
+	}
+	return strings.Join(families, "+")
+}
+
+func (si SecurityInfo) hasAKM(match func(string) bool) bool {
+	for _, akm := range si.AKMs {
+		if match(akm) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSAE(akm string) bool {
+	return strings.Contains(akm, "SAE")
+}
+
+// akmFamily reduces a normalised AKM name to PSK, 802.1X or OWE.
+func akmFamily(akm string) string {
+	switch {
+	case akm == "OWE":
+		return "OWE"
+	case strings.Contains(akm, "PSK"), isSAE(akm):
+		return "PSK"
+	case strings.Contains(akm, "802.1X"), strings.HasPrefix(akm, "SuiteB"), strings.Contains(akm, "FILS"):
+		return "802.1X"
+	default:
+		return akm
+	}
+}
+
+// addUnique appends s to list if not already present.
+func addUnique(list []string, s ...string) []string {
+	for _, v := range s {
+		found := false
+		for _, have := range list {
+			if have == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// ── Raw element decoding ────────────────────────────────────────────────────
+
+// decodeRSN merges an RSN element body into si.
+func (si *SecurityInfo) decodeRSN(data []byte) {
+	si.RSN = true
+	si.decodeSuites(data, true)
+}
+
+// decodeWPA merges a legacy WPA vendor element body (after the OUI and
+// type bytes) into si.
+func (si *SecurityInfo) decodeWPA(data []byte) {
+	si.WPA = true
+	si.decodeSuites(data, false)
+}
+
+// decodeSuites parses version, group cipher, pairwise cipher list, AKM list
+// and (for RSN) capabilities. Missing trailing fields are tolerated.
+func (si *SecurityInfo) decodeSuites(data []byte, rsn bool) {
+	if len(data) < 2 {
+		return
+	}
+	data = data[2:] // version
+
+	if len(data) < 4 {
+		return
+	}
+	if si.GroupCipher == "" || rsn {
+		si.GroupCipher = suiteName(data[:4], cipherNames)
+	}
+	data = data[4:]
+
+	for _, list := range []*[]string{&si.PairwiseCiphers, &si.AKMs} {
+		if len(data) < 2 {
+			return
+		}
+		count := int(binary.LittleEndian.Uint16(data))
+		data = data[2:]
+		for i := 0; i < count && len(data) >= 4; i++ {
+			names := cipherNames
+			if list == &si.AKMs {
+				names = akmNames
+			}
+			*list = addUnique(*list, suiteName(data[:4], names))
+			data = data[4:]
+		}
+	}
+
+	if rsn && len(data) >= 2 {
+		si.HasCapabilities = true
+		si.RSNCapabilities = binary.LittleEndian.Uint16(data)
+		si.PMFRequired = si.RSNCapabilities&rsnCapMFPRequired != 0
+		si.PMFCapable = si.RSNCapabilities&rsnCapMFPCapable != 0
+	}
+}
+
+// suiteName names a 4-byte suite selector. IEEE and (for WPA) Microsoft
+// selectors share type numbering for the values WPA uses; anything else
+// is shown as "oui:type".
+func suiteName(sel []byte, names map[byte]string) string {
+	oui := uint32(sel[0])<<16 | uint32(sel[1])<<8 | uint32(sel[2])
+	typ := sel[3]
+	if oui == ouiIEEE || oui == ouiMicrosoft {
+		if name, ok := names[typ]; ok {
+			return name
+		}
+	}
+	return fmt.Sprintf("%02X-%02X-%02X:%d", sel[0], sel[1], sel[2], typ)
+}
+
+// ── iw text decoding ────────────────────────────────────────────────────────
+
+// parseSecurity decodes the RSN and WPA sections of an iw BSS block.
+func parseSecurity(block string) SecurityInfo {
+	var si SecurityInfo
+
+	if lines, ok := iwSection(block, "RSN"); ok {
+		si.RSN = true
+		si.mergeIW(iwFields(lines), true)
+	}
+	if lines, ok := iwSection(block, "WPA"); ok {
+		si.WPA = true
+		si.mergeIW(iwFields(lines), false)
+	}
+	if m := iwCapabilityRe.FindStringSubmatch(block); len(m) > 1 {
+		si.Privacy = strings.Contains(m[1], "Privacy")
+	}
+
+	return si
+}
+
+// mergeIW merges the fields of one iw RSN or WPA section into si.
+func (si *SecurityInfo) mergeIW(fields map[string]string, rsn bool) {
+	if g := fields["Group cipher"]; g != "" && (si.GroupCipher == "" || rsn) {
+		si.GroupCipher = iwCipher(g)
+	}
+	for _, c := range strings.Fields(fields["Pairwise ciphers"]) {
+		si.PairwiseCiphers = addUnique(si.PairwiseCiphers, iwCipher(c))
+	}
+	si.AKMs = addUnique(si.AKMs, splitIWAKMs(fields["Authentication suites"])...)
+
+	if caps, ok := fields["Capabilities"]; ok && rsn {
+		if m := iwHexSuffixRe.FindStringSubmatch(caps); len(m) > 1 {
+			v, _ := strconv.ParseUint(m[1], 16, 16)
+			si.HasCapabilities = true
+			si.RSNCapabilities = uint16(v)
+		}
+		si.PMFRequired = strings.Contains(caps, "MFP-required") || si.RSNCapabilities&rsnCapMFPRequired != 0
+		si.PMFCapable = strings.Contains(caps, "MFP-capable") || si.RSNCapabilities&rsnCapMFPCapable != 0
+	}
+}
+
+// iwCipher normalises iw's cipher spelling.
+func iwCipher(c string) string {
+	if c == "Use group cipher suite" {
+		return "GROUP"
+	}
+	return c
+}
+
+// splitIWAKMs splits iw's space-separated AKM list, matching the known
+// multi-word names longest-first. Unknown words are kept as-is.
+func splitIWAKMs(s string) []string {
+	var akms []string
+	s = strings.TrimSpace(s)
+	for s != "" {
+		matched := false
+		for _, name := range iwAKMOrder {
+			if strings.HasPrefix(s, name) && (len(s) == len(name) || s[len(name)] == ' ') {
+				akms = append(akms, iwAKMNames[name])
+				s = strings.TrimSpace(s[len(name):])
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			word := strings.Fields(s)[0]
+			akms = append(akms, word)
+			s = strings.TrimSpace(s[len(word):])
+		}
+	}
+	return akms
+}
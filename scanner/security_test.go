@@ -0,0 +1,261 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// suite builds a 4-byte suite selector.
+func suite(oui uint32, typ byte) []byte {
+	return []byte{byte(oui >> 16), byte(oui >> 8), byte(oui), typ}
+}
+
+// suiteBody builds an RSN or WPA element body. caps is omitted when nil.
+func suiteBody(group []byte, pairwise, akms [][]byte, caps []byte) []byte {
+	b := []byte{1, 0, group[0], group[1], group[2], group[3]}
+	for _, list := range [][][]byte{pairwise, akms} {
+		b = append(b, byte(len(list)), 0)
+		for _, s := range list {
+			b = append(b, s...)
+		}
+	}
+	return append(b, caps...)
+}
+
+func TestDecodeRSNTables(t *testing.T) {
+	ccmp := suite(ouiIEEE, 4)
+	psk := suite(ouiIEEE, 2)
+	for typ, name := range akmNames {
+		var si SecurityInfo
+		si.decodeRSN(suiteBody(ccmp, [][]byte{ccmp}, [][]byte{suite(ouiIEEE, typ)}, nil))
+		if !reflect.DeepEqual(si.AKMs, []string{name}) {
+			t.Errorf("AKM %d: got %q, want %q", typ, si.AKMs, name)
+		}
+	}
+	for typ, name := range cipherNames {
+		var si SecurityInfo
+		si.decodeRSN(suiteBody(suite(ouiIEEE, typ), [][]byte{suite(ouiIEEE, typ)}, [][]byte{psk}, nil))
+		if si.GroupCipher != name || !reflect.DeepEqual(si.PairwiseCiphers, []string{name}) {
+			t.Errorf("cipher %d: got %s %q, want %s", typ, si.GroupCipher, si.PairwiseCiphers, name)
+		}
+	}
+
+	var si SecurityInfo
+	si.decodeRSN(suiteBody(ccmp, [][]byte{ccmp, suite(0x001018, 4)}, [][]byte{suite(ouiIEEE, 99)}, nil))
+	if want := []string{"CCMP", "00-10-18:4"}; !reflect.DeepEqual(si.PairwiseCiphers, want) {
+		t.Errorf("vendor cipher: got %q, want %q", si.PairwiseCiphers, want)
+	}
+	if want := []string{"00-0F-AC:99"}; !reflect.DeepEqual(si.AKMs, want) {
+		t.Errorf("unknown AKM: got %q, want %q", si.AKMs, want)
+	}
+}
+
+func TestDecodeRSNCapabilities(t *testing.T) {
+	ccmp := suite(ouiIEEE, 4)
+	akms := [][]byte{suite(ouiIEEE, 8)}
+	tests := []struct {
+		name     string
+		caps     []byte
+		has      bool
+		capable  bool
+		required bool
+	}{
+		{"absent", nil, false, false, false},
+		{"none", []byte{0x0c, 0x00}, true, false, false},
+		{"MFPC", []byte{0x8c, 0x00}, true, true, false},
+		{"MFPR and MFPC", []byte{0xcc, 0x00}, true, true, true},
+		{"MFPR only", []byte{0x40, 0x00}, true, false, true},
+		{"truncated", []byte{0xcc}, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var si SecurityInfo
+			si.decodeRSN(suiteBody(ccmp, [][]byte{ccmp}, akms, tt.caps))
+			if si.HasCapabilities != tt.has || si.PMFCapable != tt.capable || si.PMFRequired != tt.required {
+				t.Errorf("got caps %v MFPC %v MFPR %v, want %v %v %v",
+					si.HasCapabilities, si.PMFCapable, si.PMFRequired, tt.has, tt.capable, tt.required)
+			}
+		})
+	}
+}
+
+func TestApplyIEsSecurity(t *testing.T) {
+	ie := func(id byte, data []byte) []byte { return append([]byte{id, byte(len(data))}, data...) }
+	wpa := func(body []byte) []byte { return ie(ieVendor, append(suite(ouiMicrosoft, msTypeWPA), body...)) }
+
+	ccmp, tkip := suite(ouiIEEE, 4), suite(ouiIEEE, 2)
+	msTKIP, msPSK := suite(ouiMicrosoft, 2), suite(ouiMicrosoft, 2)
+	psk, sae := suite(ouiIEEE, 2), suite(ouiIEEE, 8)
+
+	tests := []struct {
+		name  string
+		ies   []byte
+		cap   uint16
+		class string
+		label string
+		mode  AuthMode
+	}{
+		{"open", nil, 0, "OPEN", "OPEN", AuthOpen},
+		{"WEP", nil, capPrivacy, "WEP", "WEP", AuthWEP},
+		{
+			"WPA2/WPA3 transition",
+			ie(ieRSN, suiteBody(ccmp, [][]byte{ccmp}, [][]byte{psk, sae}, []byte{0x8c, 0x00})),
+			capPrivacy, "WPA3", "WPA2/WPA3-Transition PSK CCMP PMF-opt", AuthPSKSAE,
+		},
+		{
+			"WPA3 only",
+			ie(ieRSN, suiteBody(ccmp, [][]byte{ccmp}, [][]byte{sae}, []byte{0xcc, 0x00})),
+			capPrivacy, "WPA3", "WPA3 PSK CCMP PMF-req", AuthSAE,
+		},
+		{
+			"WPA2/WPA mixed",
+			append(ie(ieRSN, suiteBody(tkip, [][]byte{ccmp, tkip}, [][]byte{psk}, []byte{0x00, 0x00})),
+				wpa(suiteBody(msTKIP, [][]byte{msTKIP}, [][]byte{msPSK}, nil))...),
+			capPrivacy, "WPA2/WPA", "WPA2/WPA PSK CCMP+TKIP", AuthPSK,
+		},
+		{
+			"WPA only",
+			wpa(suiteBody(msTKIP, [][]byte{msTKIP}, [][]byte{msPSK}, nil)),
+			capPrivacy, "WPA", "WPA PSK TKIP", AuthPSK,
+		},
+		{
+			"OWE",
+			ie(ieRSN, suiteBody(ccmp, [][]byte{ccmp}, [][]byte{suite(ouiIEEE, 18)}, []byte{0xcc, 0x00})),
+			capPrivacy, "OWE", "OWE CCMP PMF-req", AuthOWE,
+		},
+		{
+			"WPA3-Enterprise",
+			ie(ieRSN, suiteBody(ccmp, [][]byte{ccmp}, [][]byte{suite(ouiIEEE, 5)}, []byte{0xcc, 0x00})),
+			capPrivacy, "WPA3-ENT", "WPA3-Enterprise 802.1X CCMP PMF-req", AuthEAP,
+		},
+		{
+			"WPA2-Enterprise with PSK",
+			ie(ieRSN, suiteBody(ccmp, [][]byte{ccmp}, [][]byte{suite(ouiIEEE, 1), psk}, nil)),
+			capPrivacy, "WPA2-ENT", "WPA2-Enterprise 802.1X+PSK CCMP", AuthEAP,
+		},
+		{
+			"WPA3-Enterprise 192-bit",
+			ie(ieRSN, suiteBody(suite(ouiIEEE, 9), [][]byte{suite(ouiIEEE, 9)}, [][]byte{suite(ouiIEEE, 12)}, []byte{0xcc, 0x00})),
+			capPrivacy, "WPA3-ENT-192", "WPA3-Enterprise-192 802.1X GCMP-256 PMF-req", AuthEAPSuite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var n Network
+			applyIEs(&n, tt.ies, tt.cap)
+			si := n.SecurityInfo
+			if n.Security != tt.class || si.Label() != tt.label || si.AuthMode() != tt.mode {
+				t.Errorf("got %s %q %s, want %s %q %s", n.Security, si.Label(), si.AuthMode(), tt.class, tt.label, tt.mode)
+			}
+		})
+	}
+}
+
+func TestParseSecurity(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  SecurityInfo
+		label string
+	}{
+		{
+			"transition",
+			`BSS a4:2b:8c:01:02:03(on wlan0)
+	capability: ESS Privacy ShortSlotTime (0x0411)
+	RSN:	 * Version: 1
+		 * Group cipher: CCMP
+		 * Pairwise ciphers: CCMP
+		 * Authentication suites: PSK SAE
+		 * Capabilities: 16-PTKSA-RC 1-GTKSA-RC MFP-capable (0x008c)
+`,
+			SecurityInfo{
+				RSN: true, Privacy: true, GroupCipher: "CCMP",
+				PairwiseCiphers: []string{"CCMP"}, AKMs: []string{"PSK", "SAE"},
+				PMFCapable: true, RSNCapabilities: 0x008c, HasCapabilities: true,
+			},
+			"WPA2/WPA3-Transition PSK CCMP PMF-opt",
+		},
+		{
+			"mixed WPA and WPA2",
+			`BSS b0:c7:45:3a:91:de(on wlan0)
+	capability: ESS Privacy ShortSlotTime (0x0411)
+	RSN:	 * Version: 1
+		 * Group cipher: TKIP
+		 * Pairwise ciphers: CCMP TKIP
+		 * Authentication suites: PSK
+		 * Capabilities: 1-PTKSA-RC 1-GTKSA-RC (0x0000)
+	WPA:	 * Version: 1
+		 * Group cipher: TKIP
+		 * Pairwise ciphers: TKIP
+		 * Authentication suites: PSK
+`,
+			SecurityInfo{
+				RSN: true, WPA: true, Privacy: true, GroupCipher: "TKIP",
+				PairwiseCiphers: []string{"CCMP", "TKIP"}, AKMs: []string{"PSK"},
+				HasCapabilities: true,
+			},
+			"WPA2/WPA PSK CCMP+TKIP",
+		},
+		{
+			"enterprise MFP from bits",
+			`BSS 00:11:22:33:44:55(on wlan0)
+	capability: ESS Privacy (0x0011)
+	RSN:	 * Version: 1
+		 * Group cipher: CCMP
+		 * Pairwise ciphers: CCMP
+		 * Authentication suites: FT/IEEE 802.1X IEEE 802.1X/SHA-256
+		 * Capabilities: 1-PTKSA-RC 1-GTKSA-RC (0x00c0)
+`,
+			SecurityInfo{
+				RSN: true, Privacy: true, GroupCipher: "CCMP",
+				PairwiseCiphers: []string{"CCMP"}, AKMs: []string{"FT-802.1X", "802.1X-SHA256"},
+				PMFCapable: true, PMFRequired: true, RSNCapabilities: 0x00c0, HasCapabilities: true,
+			},
+			"WPA3-Enterprise 802.1X CCMP PMF-req",
+		},
+		{
+			"WEP",
+			`BSS 00:11:22:33:44:66(on wlan0)
+	capability: ESS Privacy (0x0011)
+`,
+			SecurityInfo{Privacy: true},
+			"WEP",
+		},
+		{
+			"open",
+			`BSS 00:11:22:33:44:77(on wlan0)
+	capability: ESS ShortSlotTime (0x0401)
+`,
+			SecurityInfo{},
+			"OPEN",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSecurity(tt.block)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSecurity() = %+v, want %+v", got, tt.want)
+			}
+			if l := got.Label(); l != tt.label {
+				t.Errorf("Label() = %q, want %q", l, tt.label)
+			}
+		})
+	}
+}
+
+func TestSplitIWAKMs(t *testing.T) {
+	for iw, name := range iwAKMNames {
+		if got := splitIWAKMs(iw); !reflect.DeepEqual(got, []string{name}) {
+			t.Errorf("splitIWAKMs(%q) = %q, want [%s]", iw, got, name)
+		}
+	}
+
+	got := splitIWAKMs("FT/IEEE 802.1X/SHA-384 IEEE 802.1X/SUITE-B-192 SAE FT/SAE-EXT-KEY 00-0f-ac:99")
+	want := []string{"FT-802.1X-SHA384", "SuiteB-192", "SAE", "FT-SAE-EXT-KEY", "00-0f-ac:99"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitIWAKMs() = %q, want %q", got, want)
+	}
+	if got := splitIWAKMs("  "); got != nil {
+		t.Errorf("splitIWAKMs(blank) = %q, want nil", got)
+	}
+}
@@ -1,13 +1,21 @@
 package scanner
 
 import (
+	"fmt"
 	"sync"
 	"time"
+
+	"wifiscanner/scanner/channel"
 )
 
 const (
 	maxHistory = 10
 	newTimeout = 30 * time.Second
+
+	// tsfSlack is how far a TSF may go backwards before it counts as a
+	// reboot. Results merged from several interfaces, or cached by the
+	// driver, can be slightly out of order.
+	tsfSlack = 5 * time.Second
 )
 
 // sparkBlocks maps signal intensity (0–7) to Unicode block characters.
@@ -21,6 +29,29 @@ type NetworkState struct {
 	SignalHistory []int
 	MinSignal     int
 	MaxSignal     int
+	SeenCount     int // scans the network appeared in
+	firstScan     int // session scan number when first seen
+
+	// AP-reported load over the last maxHistory scans that carried it
+	LoadHistory  []LoadSample
+	PeakStations int
+
+	// Operating channel as of the last scan, and how often it changed
+	Channel      int
+	Frequency    int
+	Radar        channel.Radar
+	ChannelMoves int
+
+	// TSF as of the last scan that reported one, and detected reboots
+	TSF        uint64
+	Reboots    int
+	LastReboot time.Time
+}
+
+// LoadSample is one BSS Load reading.
+type LoadSample struct {
+	Time time.Time
+	BSSLoad
 }
 
 // IsNew returns true if this network was first seen within the last 30 seconds.
@@ -48,10 +79,48 @@ func (ns *NetworkState) Sparkline() string {
 	return string(runes)
 }
 
+// LoadSparkline returns a Unicode sparkline of channel utilisation over the
+// recorded load history.
+func (ns *NetworkState) LoadSparkline() string {
+	if len(ns.LoadHistory) == 0 {
+		return ""
+	}
+	runes := make([]rune, len(ns.LoadHistory))
+	for i, l := range ns.LoadHistory {
+		// Map utilisation 0–255 into 0–7
+		idx := l.Utilisation * 7 / 255
+		if idx > 7 {
+			idx = 7
+		}
+		runes[i] = sparkBlocks[idx]
+	}
+	return string(runes)
+}
+
+// AlertKind classifies a session alert.
+type AlertKind string
+
+const (
+	AlertChannelMove AlertKind = "channel-move" // BSS changed channel
+	AlertRadar       AlertKind = "radar"        // BSS left a DFS channel, likely after detecting radar
+	AlertReboot      AlertKind = "reboot"       // BSS's TSF went backwards
+)
+
+// Alert is a noteworthy change the session spotted between scans.
+type Alert struct {
+	Time    time.Time
+	Kind    AlertKind
+	BSSID   string
+	SSID    string
+	Message string
+}
+
 // Session tracks network state across multiple scan cycles.
 type Session struct {
 	mu     sync.Mutex
 	states map[string]*NetworkState
+	scans  int
+	alerts []Alert
 }
 
 // NewSession creates an empty session tracker.
@@ -69,6 +138,7 @@ func (s *Session) Update(networks []Network) []string {
 
 	var newBSSIDs []string
 	now := time.Now()
+	s.scans++
 
 	for _, net := range networks {
 		state, exists := s.states[net.BSSID]
@@ -78,12 +148,16 @@ func (s *Session) Update(networks []Network) []string {
 				FirstSeen: now,
 				MinSignal: net.Signal,
 				MaxSignal: net.Signal,
+				firstScan: s.scans,
 			}
 			s.states[net.BSSID] = state
 			newBSSIDs = append(newBSSIDs, net.BSSID)
 		}
 
 		state.LastSeen = now
+		state.SeenCount++
+		s.trackChannel(state, net, now)
+		s.trackTSF(state, net, now)
 
 		// Track min/max
 		if net.Signal < state.MinSignal {
@@ -98,11 +172,79 @@ func (s *Session) Update(networks []Network) []string {
 		if len(state.SignalHistory) > maxHistory {
 			state.SignalHistory = state.SignalHistory[len(state.SignalHistory)-maxHistory:]
 		}
+
+		if net.BSSLoad != nil {
+			state.LoadHistory = append(state.LoadHistory, LoadSample{Time: now, BSSLoad: *net.BSSLoad})
+			if len(state.LoadHistory) > maxHistory {
+				state.LoadHistory = state.LoadHistory[len(state.LoadHistory)-maxHistory:]
+			}
+			if net.BSSLoad.StationCount > state.PeakStations {
+				state.PeakStations = net.BSSLoad.StationCount
+			}
+		}
 	}
 
 	return newBSSIDs
 }
 
+// trackChannel records the network's operating channel, raising an alert
+// when a known BSS moves. Leaving a DFS channel is the usual sign that the
+// AP detected radar and had to vacate it.
+func (s *Session) trackChannel(state *NetworkState, net Network, now time.Time) {
+	if net.Frequency == 0 {
+		return
+	}
+	radar := net.Span().Radar()
+	if state.Frequency != 0 && state.Frequency != net.Frequency {
+		state.ChannelMoves++
+		alert := Alert{
+			Time:  now,
+			Kind:  AlertChannelMove,
+			BSSID: net.BSSID,
+			SSID:  net.SSID,
+			Message: fmt.Sprintf("%s moved from channel %d to %d",
+				net.SSID, state.Channel, net.Channel),
+		}
+		if state.Radar != channel.RadarNone {
+			alert.Kind = AlertRadar
+			alert.Message = fmt.Sprintf("%s left %s channel %d for %d, likely radar detection",
+				net.SSID, state.Radar, state.Channel, net.Channel)
+		}
+		s.alerts = append(s.alerts, alert)
+	}
+	state.Channel, state.Frequency, state.Radar = net.Channel, net.Frequency, radar
+}
+
+// trackTSF records the network's TSF, raising an alert when it goes
+// backwards: the AP restarted its BSS, usually because it rebooted.
+func (s *Session) trackTSF(state *NetworkState, net Network, now time.Time) {
+	if net.TSF == 0 {
+		return
+	}
+	if state.TSF != 0 && tsfDuration(net.TSF)+tsfSlack < tsfDuration(state.TSF) {
+		state.Reboots++
+		state.LastReboot = now
+		s.alerts = append(s.alerts, Alert{
+			Time:  now,
+			Kind:  AlertReboot,
+			BSSID: net.BSSID,
+			SSID:  net.SSID,
+			Message: fmt.Sprintf("%s rebooted: uptime reset from %s to %s",
+				net.SSID, FormatUptime(tsfDuration(state.TSF)), FormatUptime(net.Uptime())),
+		})
+	}
+	state.TSF = net.TSF
+}
+
+// Alerts returns the alerts raised since the last call and clears them.
+func (s *Session) Alerts() []Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	alerts := s.alerts
+	s.alerts = nil
+	return alerts
+}
+
 // Get returns the state for a given BSSID, or nil if not tracked.
 func (s *Session) Get(bssid string) *NetworkState {
 	s.mu.Lock()
@@ -116,3 +258,15 @@ func (s *Session) Count() int {
 	defer s.mu.Unlock()
 	return len(s.states)
 }
+
+// Persistence returns the fraction of scans since a BSSID was first seen in
+// which it appeared, or 0 if it is not tracked.
+func (s *Session) Persistence(bssid string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[bssid]
+	if !ok {
+		return 0
+	}
+	return float64(state.SeenCount) / float64(s.scans-state.firstScan+1)
+}
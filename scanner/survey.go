@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChannelSurvey is the radio's view of one channel: its noise floor and how
+// the channel's airtime was spent while the radio listened.
+type ChannelSurvey struct {
+	Interface string
+	Frequency int  // MHz
+	InUse     bool // the channel the interface is currently tuned to
+	Noise     int  // dBm, 0 if the driver does not report it
+
+	Active   time.Duration // time spent on the channel
+	Busy     time.Duration // time the channel was sensed busy
+	Receive  time.Duration // time spent receiving
+	Transmit time.Duration // time spent transmitting
+}
+
+// Utilisation returns the busy share of the channel's active time in
+// percent, or -1 if the driver reports no airtime counters.
+func (c ChannelSurvey) Utilisation() float64 {
+	if c.Active <= 0 {
+		return -1
+	}
+	u := float64(c.Busy) / float64(c.Active) * 100
+	if u > 100 {
+		u = 100
+	}
+	return u
+}
+
+// SNR returns the signal-to-noise ratio in dB and whether the noise floor
+// is known.
+func (n Network) SNR() (int, bool) {
+	if n.Noise == 0 {
+		return 0, false
+	}
+	return n.Signal - n.Noise, true
+}
+
+// applyNoise sets each network's noise floor from the survey of its
+// primary frequency.
+func applyNoise(networks []Network, surveys []ChannelSurvey) {
+	noise := make(map[int]int)
+	for _, c := range surveys {
+		if c.Noise != 0 {
+			noise[c.Frequency] = c.Noise
+		}
+	}
+	for i := range networks {
+		if nf, ok := noise[networks[i].Frequency]; ok {
+			networks[i].Noise = nf
+		}
+	}
+}
+
+// sortSurveys orders surveys by frequency, then interface.
+func sortSurveys(surveys []ChannelSurvey) {
+	sort.Slice(surveys, func(i, j int) bool {
+		if surveys[i].Frequency != surveys[j].Frequency {
+			return surveys[i].Frequency < surveys[j].Frequency
+		}
+		return surveys[i].Interface < surveys[j].Interface
+	})
+}
+
+var (
+	iwSurveyFreqRe = regexp.MustCompile(`^(\d+) MHz`)
+	iwSurveyNumRe  = regexp.MustCompile(`^-?\d+`)
+)
+
+// parseSurveyDump parses `iw dev <iface> survey dump` output.
+func parseSurveyDump(iface, output string) []ChannelSurvey {
+	var surveys []ChannelSurvey
+	for _, block := range strings.Split(output, "Survey data from ")[1:] {
+		c := ChannelSurvey{Interface: iface}
+		for _, line := range strings.Split(block, "\n")[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+			if !ok {
+				continue
+			}
+			value = strings.TrimSpace(value)
+			num, _ := strconv.Atoi(iwSurveyNumRe.FindString(value))
+			ms := time.Duration(num) * time.Millisecond
+
+			switch key {
+			case "frequency":
+				if m := iwSurveyFreqRe.FindStringSubmatch(value); m != nil {
+					c.Frequency, _ = strconv.Atoi(m[1])
+				}
+				c.InUse = strings.Contains(value, "[in use]")
+			case "noise":
+				c.Noise = num
+			case "channel active time":
+				c.Active = ms
+			case "channel busy time":
+				c.Busy = ms
+			case "channel receive time":
+				c.Receive = ms
+			case "channel transmit time":
+				c.Transmit = ms
+			}
+		}
+		if c.Frequency != 0 {
+			surveys = append(surveys, c)
+		}
+	}
+	return surveys
+}
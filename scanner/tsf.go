@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Uptime estimates how long the AP has been beaconing from its TSF, which
+// counts microseconds from when the BSS started. It is 0 if the TSF is
+// unknown.
+func (n Network) Uptime() time.Duration {
+	return tsfDuration(n.TSF)
+}
+
+func tsfDuration(tsf uint64) time.Duration {
+	return time.Duration(tsf) * time.Microsecond
+}
+
+// FormatUptime renders a duration the way iw prints TSF values, e.g.
+// "3d, 04:12:07".
+func FormatUptime(d time.Duration) string {
+	secs := int64(d / time.Second)
+	return fmt.Sprintf("%dd, %02d:%02d:%02d", secs/86400, secs/3600%24, secs/60%60, secs%60)
+}
+
+var iwTSFRe = regexp.MustCompile(`(?m)^\s*TSF:\s*(\d+) usec`)
+
+// parseTSF reads the line iw prints as "TSF: 1234567 usec (0d, 00:00:01)".
+func parseTSF(block string) uint64 {
+	if m := iwTSFRe.FindStringSubmatch(block); m != nil {
+		tsf, _ := strconv.ParseUint(m[1], 10, 64)
+		return tsf
+	}
+	return 0
+}
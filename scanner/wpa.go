@@ -0,0 +1,344 @@
+package scanner
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"wifiscanner/scanner/channel"
+)
+
+const (
+	wpaCtrlDir      = "/var/run/wpa_supplicant"
+	wpaReplyTimeout = 5 * time.Second
+	wpaBufSize      = 1 << 16
+)
+
+// wpaClientSeq makes each client socket path unique within the process.
+var wpaClientSeq uint32
+
+func init() {
+	register("wpa_supplicant", func() Backend { return &wpaBackend{ctrlDir: wpaCtrlDir} })
+}
+
+// wpaBackend scans through wpa_supplicant's control interface, for systems
+// that run the supplicant but ship no iw.
+type wpaBackend struct {
+	ctrlDir string
+}
+
+func (b *wpaBackend) Name() string { return "wpa_supplicant" }
+
+func (b *wpaBackend) Capabilities() Capabilities {
+	return CapActiveScan | CapRawIEs
+}
+
+// Scan requests a scan, waits for CTRL-EVENT-SCAN-RESULTS, then reads the
+// result list and per-BSS details. If the supplicant refuses to scan, its
+// cached results are returned instead.
+func (b *wpaBackend) Scan(iface string) ([]Network, error) {
+	_, networks, err := b.ScanRaw(iface)
+	return networks, err
+}
+
+// ScanRaw is Scan that also returns the SCAN_RESULTS reply followed by
+// each BSS reply, separated by blank lines.
+func (b *wpaBackend) ScanRaw(iface string) ([]byte, []Network, error) {
+	c, err := dialWPA(filepath.Join(b.ctrlDir, iface))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer c.close()
+
+	if reply, err := c.request("ATTACH"); err != nil {
+		return nil, nil, err
+	} else if reply != "OK" {
+		return nil, nil, fmt.Errorf("wpa_supplicant: ATTACH: %s", reply)
+	}
+	defer c.request("DETACH")
+
+	reply, err := c.request("SCAN")
+	if err != nil {
+		return nil, nil, err
+	}
+	if reply == "OK" || reply == "FAIL-BUSY" {
+		if err := c.waitEvent("CTRL-EVENT-SCAN-RESULTS", scanTimeout); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	results, err := c.request("SCAN_RESULTS")
+	if err != nil {
+		return nil, nil, err
+	}
+	networks := parseWPAScanResults(results)
+	raw := []string{results}
+
+	for i := range networks {
+		detail, err := c.request("BSS " + networks[i].BSSID)
+		if err != nil {
+			return nil, nil, err
+		}
+		applyWPABSS(&networks[i], detail)
+		raw = append(raw, detail)
+	}
+
+	return []byte(strings.Join(raw, "\n\n")), networks, nil
+}
+
+// Interfaces lists the control sockets wpa_supplicant has created.
+func (b *wpaBackend) Interfaces() ([]string, error) {
+	entries, err := os.ReadDir(b.ctrlDir)
+	if err != nil {
+		return nil, fmt.Errorf("wpa_supplicant control directory: %w", err)
+	}
+
+	var ifaces []string
+	for _, e := range entries {
+		if e.Type()&os.ModeSocket != 0 {
+			ifaces = append(ifaces, e.Name())
+		}
+	}
+	if len(ifaces) == 0 {
+		return nil, fmt.Errorf("no wpa_supplicant control socket in %s", b.ctrlDir)
+	}
+	return ifaces, nil
+}
+
+// parseWPAScanResults parses a SCAN_RESULTS reply:
+//
+//	bssid / frequency / signal level / flags / ssid
+//	00:11:22:33:44:55	2412	-45	[WPA2-PSK-CCMP][ESS]	MyNet
+func parseWPAScanResults(reply string) []Network {
+	var networks []Network
+	now := time.Now()
+
+	for _, line := range strings.Split(reply, "\n") {
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) < 4 || len(fields[0]) != 17 {
+			continue // header or blank line
+		}
+
+		n := Network{
+			BSSID:        strings.ToUpper(fields[0]),
+			SecurityInfo: wpaSecurity(fields[3]),
+			LastSeen:     now,
+		}
+		n.Security = n.SecurityInfo.Class()
+		n.Frequency, _ = strconv.Atoi(fields[1])
+		n.Signal, _ = strconv.Atoi(fields[2])
+		n.Channel = channel.Number(n.Frequency)
+		if len(fields) == 5 {
+			n.SSID = fields[4]
+		}
+		if n.SSID == "" {
+			n.SSID = "<hidden>"
+		}
+
+		networks = append(networks, n)
+	}
+
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].Signal > networks[j].Signal
+	})
+
+	return networks
+}
+
+// applyWPABSS enriches n from a 'BSS <bssid>' reply of key=value lines.
+func applyWPABSS(n *Network, reply string) {
+	detail := make(map[string]string)
+	for _, line := range strings.Split(reply, "\n") {
+		if k, v, ok := strings.Cut(line, "="); ok {
+			detail[k] = v
+		}
+	}
+
+	// Decimal, zero-padded to 16 digits
+	n.TSF, _ = strconv.ParseUint(detail["tsf"], 10, 64)
+
+	ies, err := hex.DecodeString(detail["ie"])
+	if err != nil || len(ies) == 0 {
+		return
+	}
+	capability, _ := strconv.ParseUint(strings.TrimPrefix(detail["capabilities"], "0x"), 16, 16)
+
+	// The IEs carry what the flags leave out, such as PMF and the DS channel
+	ssid := n.SSID
+	applyIEs(n, ies, uint16(capability))
+	if n.SSID == "" {
+		n.SSID = ssid
+	}
+}
+
+// wpaKeyMgmt maps wpa_supplicant's key management names to normalised AKMs.
+var wpaKeyMgmt = map[string]string{
+	"EAP":             "802.1X",
+	"PSK":             "PSK",
+	"FT/EAP":          "FT-802.1X",
+	"FT/PSK":          "FT-PSK",
+	"EAP-SHA256":      "802.1X-SHA256",
+	"PSK-SHA256":      "PSK-SHA256",
+	"SAE":             "SAE",
+	"FT/SAE":          "FT-SAE",
+	"SAE-EXT-KEY":     "SAE-EXT-KEY",
+	"FT/SAE-EXT-KEY":  "FT-SAE-EXT-KEY",
+	"EAP-SUITE-B":     "SuiteB",
+	"EAP-SUITE-B-192": "SuiteB-192",
+	"FT/EAP-SHA384":   "FT-802.1X-SHA384",
+	"FILS-SHA256":     "FILS-SHA256",
+	"FILS-SHA384":     "FILS-SHA384",
+	"FT-FILS-SHA256":  "FT-FILS-SHA256",
+	"FT-FILS-SHA384":  "FT-FILS-SHA384",
+	"OWE":             "OWE",
+}
+
+// wpaCiphers maps wpa_supplicant's cipher names to the names iw prints.
+var wpaCiphers = map[string]string{
+	"CCMP":     "CCMP",
+	"TKIP":     "TKIP",
+	"GCMP":     "GCMP-128",
+	"GCMP-256": "GCMP-256",
+	"CCMP-256": "CCMP-256",
+	"WEP40":    "WEP-40",
+	"WEP104":   "WEP-104",
+}
+
+// wpaSecurity decodes wpa_supplicant's flag string, e.g.
+// "[WPA2-PSK+SAE-CCMP][ESS]", into a SecurityInfo. Flags carry no group
+// cipher or PMF information.
+func wpaSecurity(flags string) SecurityInfo {
+	var si SecurityInfo
+	for _, f := range strings.Split(flags, "]") {
+		f = strings.TrimPrefix(f, "[")
+		switch {
+		case f == "WEP":
+			si.Privacy = true
+		case strings.HasPrefix(f, "WPA2-"), strings.HasPrefix(f, "RSN-"):
+			si.RSN = true
+			si.mergeWPAFlag(f[strings.Index(f, "-")+1:])
+		case strings.HasPrefix(f, "WPA-"):
+			si.WPA = true
+			si.mergeWPAFlag(f[len("WPA-"):])
+		}
+	}
+	return si
+}
+
+// mergeWPAFlag merges the "<keymgmt>[+...]-<cipher>[+...][-preauth]" body
+// of one protocol flag. Both halves may contain hyphens (EAP-SUITE-B-192,
+// GCMP-256), so the split is the first hyphen after which only cipher
+// names follow.
+func (si *SecurityInfo) mergeWPAFlag(body string) {
+	body = strings.TrimSuffix(body, "-preauth")
+
+	keyMgmt, ciphers := body, ""
+	for i := 0; i < len(body); i++ {
+		if body[i] != '-' {
+			continue
+		}
+		if names, ok := wpaCipherList(body[i+1:]); ok {
+			keyMgmt = body[:i]
+			ciphers = strings.Join(names, "+")
+			break
+		}
+	}
+
+	for _, k := range strings.Split(keyMgmt, "+") {
+		if k == "" || k == "None" {
+			continue
+		}
+		if akm, ok := wpaKeyMgmt[k]; ok {
+			k = akm
+		}
+		si.AKMs = addUnique(si.AKMs, k)
+	}
+	if ciphers != "" {
+		si.PairwiseCiphers = addUnique(si.PairwiseCiphers, strings.Split(ciphers, "+")...)
+	}
+}
+
+// wpaCipherList parses a "+"-separated cipher list, failing on any
+// unknown name.
+func wpaCipherList(s string) ([]string, bool) {
+	var names []string
+	for _, c := range strings.Split(s, "+") {
+		name, ok := wpaCiphers[c]
+		if !ok {
+			return nil, false
+		}
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// wpaConn is a client of the wpa_supplicant control interface: a Unix
+// datagram socket bound to a private path and connected to the daemon's.
+type wpaConn struct {
+	conn  *net.UnixConn
+	local string
+}
+
+func dialWPA(path string) (*wpaConn, error) {
+	local := filepath.Join(os.TempDir(),
+		fmt.Sprintf("wpa_ctrl_%d-%d", os.Getpid(), atomic.AddUint32(&wpaClientSeq, 1)))
+
+	conn, err := net.DialUnix("unixgram",
+		&net.UnixAddr{Name: local, Net: "unixgram"},
+		&net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("wpa_supplicant control socket: %w", err)
+	}
+	return &wpaConn{conn: conn, local: local}, nil
+}
+
+func (c *wpaConn) close() {
+	c.conn.Close()
+	os.Remove(c.local)
+}
+
+// request sends a command and returns its reply, skipping any unsolicited
+// event messages ("<N>CTRL-EVENT-...") that arrive first.
+func (c *wpaConn) request(cmd string) (string, error) {
+	if _, err := c.conn.Write([]byte(cmd)); err != nil {
+		return "", fmt.Errorf("wpa_supplicant %s: %w", cmd, err)
+	}
+
+	buf := make([]byte, wpaBufSize)
+	deadline := time.Now().Add(wpaReplyTimeout)
+	for {
+		c.conn.SetReadDeadline(deadline)
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return "", fmt.Errorf("wpa_supplicant %s: %w", cmd, err)
+		}
+		if n > 0 && buf[0] == '<' {
+			continue
+		}
+		return strings.TrimRight(string(buf[:n]), "\n"), nil
+	}
+}
+
+// waitEvent blocks until an event containing name arrives. A scan failure
+// event ends the wait early so cached results can still be read.
+func (c *wpaConn) waitEvent(name string, timeout time.Duration) error {
+	buf := make([]byte, wpaBufSize)
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		n, err := c.conn.Read(buf)
+		if err != nil {
+			return fmt.Errorf("wpa_supplicant: waiting for %s: %w", name, err)
+		}
+		msg := string(buf[:n])
+		if strings.Contains(msg, name) || strings.Contains(msg, "CTRL-EVENT-SCAN-FAILED") {
+			return nil
+		}
+	}
+}
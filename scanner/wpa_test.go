@@ -0,0 +1,220 @@
+package scanner
+
+import (
+	"net"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const fakeScanResults = "bssid / frequency / signal level / flags / ssid\n" +
+	"a4:2b:8c:01:02:03\t2437\t-48\t[WPA2-PSK+SAE-CCMP][ESS]\tHomeNet\n" +
+	"00:11:22:33:44:55\t5180\t-71\t[WPA2-EAP-SUITE-B-192-GCMP-256][ESS]\t\n"
+
+// fakeBSS are the BSS replies, keyed by the upper-case BSSID the client
+// asks for.
+var fakeBSS = map[string]string{
+	"A4:2B:8C:01:02:03": "bssid=a4:2b:8c:01:02:03\nfreq=2437\ncapabilities=0x0411\n" +
+		"tsf=0000003723000000\nie=0007486f6d654e6574030106" +
+		"30180100000fac040100000fac040200000fac02000fac088000\n",
+	"00:11:22:33:44:55": "bssid=00:11:22:33:44:55\nfreq=5180\ncapabilities=0x0011\n",
+}
+
+// fakeSupplicant answers control interface commands on a unixgram socket
+// the way wpa_supplicant does, including unsolicited events arriving ahead
+// of command replies. It records the commands it receives.
+type fakeSupplicant struct {
+	conn *net.UnixConn
+	cmds chan string
+}
+
+func startFakeSupplicant(t *testing.T, dir, iface string) *fakeSupplicant {
+	t.Helper()
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: filepath.Join(dir, iface), Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeSupplicant{conn: conn, cmds: make(chan string, 16)}
+	t.Cleanup(func() { conn.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeSupplicant) serve() {
+	buf := make([]byte, wpaBufSize)
+	for {
+		n, client, err := f.conn.ReadFromUnix(buf)
+		if err != nil {
+			return
+		}
+		cmd := string(buf[:n])
+		f.cmds <- cmd
+
+		send := func(msgs ...string) {
+			for _, m := range msgs {
+				f.conn.WriteToUnix([]byte(m), client)
+			}
+		}
+		switch {
+		case cmd == "ATTACH", cmd == "DETACH":
+			send("OK\n")
+		case cmd == "SCAN":
+			send("<2>CTRL-EVENT-BSS-ADDED 0 a4:2b:8c:01:02:03", "OK\n",
+				"<3>CTRL-EVENT-SCAN-STARTED ", "<3>CTRL-EVENT-SCAN-RESULTS ")
+		case cmd == "SCAN_RESULTS":
+			send("<3>CTRL-EVENT-BSS-ADDED 1 00:11:22:33:44:55", fakeScanResults)
+		case strings.HasPrefix(cmd, "BSS "):
+			send(fakeBSS[strings.TrimPrefix(cmd, "BSS ")])
+		default:
+			send("UNKNOWN COMMAND\n")
+		}
+	}
+}
+
+func TestWPAScanRaw(t *testing.T) {
+	dir := t.TempDir()
+	f := startFakeSupplicant(t, dir, "wlan0")
+
+	b := &wpaBackend{ctrlDir: dir}
+	raw, networks, err := b.ScanRaw("wlan0")
+	if err != nil {
+		t.Fatalf("ScanRaw: %v", err)
+	}
+
+	wantRaw := strings.Join([]string{
+		strings.TrimRight(fakeScanResults, "\n"),
+		strings.TrimRight(fakeBSS["A4:2B:8C:01:02:03"], "\n"),
+		strings.TrimRight(fakeBSS["00:11:22:33:44:55"], "\n"),
+	}, "\n\n")
+	if string(raw) != wantRaw {
+		t.Errorf("raw output:\n%s\nwant:\n%s", raw, wantRaw)
+	}
+
+	if len(networks) != 2 {
+		t.Fatalf("got %d networks, want 2", len(networks))
+	}
+	home, ent := networks[0], networks[1]
+	if home.BSSID != "A4:2B:8C:01:02:03" || home.SSID != "HomeNet" || home.Signal != -48 {
+		t.Errorf("got %s %q %d, want A4:2B:8C:01:02:03 HomeNet -48", home.BSSID, home.SSID, home.Signal)
+	}
+	if home.Frequency != 2437 || home.Channel != 6 {
+		t.Errorf("got frequency %d channel %d, want 2437 6", home.Frequency, home.Channel)
+	}
+	if home.TSF != 3723000000 {
+		t.Errorf("TSF = %d, want 3723000000", home.TSF)
+	}
+	if len(home.IEs) != 38 {
+		t.Errorf("got %d IE bytes, want 38", len(home.IEs))
+	}
+	// The RSN element adds the PMF bits the flags leave out
+	if home.Security != "WPA3" || !home.SecurityInfo.PMFCapable {
+		t.Errorf("security = %s, PMF capable %v; want WPA3, true", home.Security, home.SecurityInfo.PMFCapable)
+	}
+	if ent.SSID != "<hidden>" || ent.Channel != 36 || ent.Security != "WPA3-ENT-192" {
+		t.Errorf("got %q channel %d %s, want <hidden> 36 WPA3-ENT-192", ent.SSID, ent.Channel, ent.Security)
+	}
+	if ent.IEs != nil {
+		t.Errorf("IEs = % x, want none", ent.IEs)
+	}
+
+	var cmds []string
+	for len(f.cmds) > 0 {
+		cmds = append(cmds, <-f.cmds)
+	}
+	wantCmds := []string{"ATTACH", "SCAN", "SCAN_RESULTS", "BSS A4:2B:8C:01:02:03", "BSS 00:11:22:33:44:55", "DETACH"}
+	if !reflect.DeepEqual(cmds, wantCmds) {
+		t.Errorf("commands = %q, want %q", cmds, wantCmds)
+	}
+}
+
+func TestWPAConnEvents(t *testing.T) {
+	dir := t.TempDir()
+	startFakeSupplicant(t, dir, "wlan0")
+
+	c, err := dialWPA(filepath.Join(dir, "wlan0"))
+	if err != nil {
+		t.Fatalf("dialWPA: %v", err)
+	}
+	defer c.close()
+
+	// The BSS-ADDED event ahead of the reply is skipped
+	if reply, err := c.request("SCAN"); err != nil || reply != "OK" {
+		t.Fatalf("SCAN = %q, %v; want OK", reply, err)
+	}
+	// SCAN-STARTED is read and ignored on the way to SCAN-RESULTS
+	if err := c.waitEvent("CTRL-EVENT-SCAN-RESULTS", time.Second); err != nil {
+		t.Fatalf("waitEvent: %v", err)
+	}
+	if reply, err := c.request("SCAN_RESULTS"); err != nil || !strings.HasPrefix(reply, "bssid / frequency") {
+		t.Errorf("SCAN_RESULTS = %q, %v; want the result table", reply, err)
+	}
+	if err := c.waitEvent("CTRL-EVENT-SCAN-RESULTS", 50*time.Millisecond); err == nil {
+		t.Error("waitEvent returned with no event pending")
+	}
+}
+
+func TestWPASecurity(t *testing.T) {
+	tests := []struct {
+		flags    string
+		rsn, wpa bool
+		akms     []string
+		ciphers  []string
+		class    string
+	}{
+		{"[WPA2-PSK+SAE-CCMP][ESS]", true, false, []string{"PSK", "SAE"}, []string{"CCMP"}, "WPA3"},
+		{"[WPA2-EAP-SUITE-B-192-GCMP-256][ESS]", true, false, []string{"SuiteB-192"}, []string{"GCMP-256"}, "WPA3-ENT-192"},
+		{"[WPA-PSK-TKIP][WPA2-PSK-CCMP+TKIP][ESS]", true, true, []string{"PSK"}, []string{"TKIP", "CCMP"}, "WPA2/WPA"},
+		{"[WPA2-EAP+FT/EAP-CCMP-preauth][ESS]", true, false, []string{"802.1X", "FT-802.1X"}, []string{"CCMP"}, "WPA2-ENT"},
+		{"[RSN-SAE+FT/SAE-CCMP][ESS]", true, false, []string{"SAE", "FT-SAE"}, []string{"CCMP"}, "WPA3"},
+		{"[WPA2-OWE-CCMP][ESS]", true, false, []string{"OWE"}, []string{"CCMP"}, "OWE"},
+		{"[WEP][ESS]", false, false, nil, nil, "WEP"},
+		{"[ESS]", false, false, nil, nil, "OPEN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.flags, func(t *testing.T) {
+			si := wpaSecurity(tt.flags)
+			if si.RSN != tt.rsn || si.WPA != tt.wpa {
+				t.Errorf("RSN %v WPA %v, want %v %v", si.RSN, si.WPA, tt.rsn, tt.wpa)
+			}
+			if !reflect.DeepEqual(si.AKMs, tt.akms) {
+				t.Errorf("AKMs = %q, want %q", si.AKMs, tt.akms)
+			}
+			if !reflect.DeepEqual(si.PairwiseCiphers, tt.ciphers) {
+				t.Errorf("ciphers = %q, want %q", si.PairwiseCiphers, tt.ciphers)
+			}
+			if class := si.Class(); class != tt.class {
+				t.Errorf("class = %s, want %s", class, tt.class)
+			}
+		})
+	}
+}
+
+func TestMergeWPAFlag(t *testing.T) {
+	tests := []struct {
+		body    string
+		akms    []string
+		ciphers []string
+	}{
+		{"PSK-CCMP", []string{"PSK"}, []string{"CCMP"}},
+		{"EAP-SUITE-B-192-GCMP-256", []string{"SuiteB-192"}, []string{"GCMP-256"}},
+		{"EAP-SHA256-CCMP-256+GCMP", []string{"802.1X-SHA256"}, []string{"CCMP-256", "GCMP-128"}},
+		{"SAE-EXT-KEY-GCMP-256", []string{"SAE-EXT-KEY"}, []string{"GCMP-256"}},
+		{"None-CCMP", nil, []string{"CCMP"}},
+		{"PSK", []string{"PSK"}, nil},
+		{"PSK-CCMP-preauth", []string{"PSK"}, []string{"CCMP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			var si SecurityInfo
+			si.mergeWPAFlag(tt.body)
+			if !reflect.DeepEqual(si.AKMs, tt.akms) {
+				t.Errorf("AKMs = %q, want %q", si.AKMs, tt.akms)
+			}
+			if !reflect.DeepEqual(si.PairwiseCiphers, tt.ciphers) {
+				t.Errorf("ciphers = %q, want %q", si.PairwiseCiphers, tt.ciphers)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// WPS vendor element (Microsoft OUI, type 4) and the Wi-Fi Simple
+// Configuration attributes read from it.
+const (
+	msTypeWPS = 4
+
+	wpsAttrDeviceName        = 0x1011
+	wpsAttrManufacturer      = 0x1021
+	wpsAttrModelName         = 0x1023
+	wpsAttrModelNumber       = 0x1024
+	wpsAttrSelectedRegistrar = 0x1041
+	wpsAttrSerialNumber      = 0x1042
+	wpsAttrState             = 0x1044
+	wpsAttrUUIDE             = 0x1047
+	wpsAttrVendorExt         = 0x1049
+	wpsAttrVersion           = 0x104A
+	wpsAttrAPSetupLocked     = 0x1057
+
+	wpsStateConfigured = 2
+	wfaVendorID        = 0x00372A // vendor extension carrying Version2
+	wfaSubVersion2     = 0
+)
+
+// WPSInfo is the Wi-Fi Protected Setup state and device identity an AP
+// advertises.
+type WPSInfo struct {
+	Version           string // "1.0", or the Version2 value such as "2.0"
+	Configured        bool   // setup state 2: credentials have been set
+	Locked            bool   // AP setup locked, e.g. after failed PIN attempts
+	SelectedRegistrar bool   // a registrar is currently accepting enrollees
+	DeviceName        string
+	Manufacturer      string
+	Model             string
+	ModelNumber       string
+	SerialNumber      string
+	UUID              string
+}
+
+// Identity returns the manufacturer, model and model number that are known,
+// e.g. "ASUSTeK RT-AC66U 1.0".
+func (w *WPSInfo) Identity() string {
+	var parts []string
+	for _, s := range []string{w.Manufacturer, w.Model, w.ModelNumber} {
+		if s != "" && (len(parts) == 0 || parts[len(parts)-1] != s) {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// decodeWPS parses the attributes of a WPS element body (after the OUI and
+// type bytes): big-endian type/length/value triplets.
+func decodeWPS(data []byte) *WPSInfo {
+	w := &WPSInfo{}
+	for len(data) >= 4 {
+		typ := binary.BigEndian.Uint16(data)
+		l := int(binary.BigEndian.Uint16(data[2:]))
+		if len(data) < 4+l {
+			break
+		}
+		v := data[4 : 4+l]
+		data = data[4+l:]
+
+		switch typ {
+		case wpsAttrVersion:
+			if l == 1 && w.Version == "" {
+				w.Version = wpsVersion(v[0])
+			}
+		case wpsAttrState:
+			w.Configured = l == 1 && v[0] == wpsStateConfigured
+		case wpsAttrAPSetupLocked:
+			w.Locked = l == 1 && v[0] != 0
+		case wpsAttrSelectedRegistrar:
+			w.SelectedRegistrar = l == 1 && v[0] != 0
+		case wpsAttrDeviceName:
+			w.DeviceName = wpsString(v)
+		case wpsAttrManufacturer:
+			w.Manufacturer = wpsString(v)
+		case wpsAttrModelName:
+			w.Model = wpsString(v)
+		case wpsAttrModelNumber:
+			w.ModelNumber = wpsString(v)
+		case wpsAttrSerialNumber:
+			w.SerialNumber = wpsString(v)
+		case wpsAttrUUIDE:
+			if l == 16 {
+				w.UUID = fmt.Sprintf("%x-%x-%x-%x-%x", v[0:4], v[4:6], v[6:8], v[8:10], v[10:16])
+			}
+		case wpsAttrVendorExt:
+			if v2, ok := wpsVersion2(v); ok {
+				w.Version = v2
+			}
+		}
+	}
+	return w
+}
+
+// wpsVersion formats a version byte: major in the high nibble.
+func wpsVersion(b byte) string {
+	return fmt.Sprintf("%d.%d", b>>4, b&0x0F)
+}
+
+// wpsVersion2 finds the Version2 subelement in a WFA vendor extension.
+func wpsVersion2(v []byte) (string, bool) {
+	if len(v) < 3 || uint32(v[0])<<16|uint32(v[1])<<8|uint32(v[2]) != wfaVendorID {
+		return "", false
+	}
+	for sub := v[3:]; len(sub) >= 2; {
+		id, l := sub[0], int(sub[1])
+		if len(sub) < 2+l {
+			break
+		}
+		if id == wfaSubVersion2 && l == 1 {
+			return wpsVersion(sub[2]), true
+		}
+		sub = sub[2+l:]
+	}
+	return "", false
+}
+
+// wpsString renders a WPS text attribute, dropping NUL and space padding.
+func wpsString(v []byte) string {
+	return escapeSSID([]byte(strings.TrimRight(string(v), "\x00 ")))
+}
+
+// parseWPS reads the "WPS" section iw prints:
+//
+//	WPS:	 * Version: 1.0
+//		 * Wi-Fi Protected Setup State: 2 (Configured)
+//		 * AP setup locked: 0x01
+//		 * Manufacturer: ASUSTeK Computer Inc.
+func parseWPS(block string) *WPSInfo {
+	lines, ok := iwSection(block, "WPS")
+	if !ok {
+		return nil
+	}
+	fields := iwFields(lines)
+	w := &WPSInfo{
+		Version:           fields["Version"],
+		Configured:        iwLeadingInt(fields["Wi-Fi Protected Setup State"]) == wpsStateConfigured,
+		Locked:            iwHexFlag(fields["AP setup locked"]),
+		SelectedRegistrar: iwHexFlag(fields["Selected Registrar"]),
+		DeviceName:        fields["Device name"],
+		Manufacturer:      fields["Manufacturer"],
+		Model:             fields["Model"],
+		ModelNumber:       fields["Model Number"],
+		SerialNumber:      fields["Serial Number"],
+		UUID:              fields["UUID"],
+	}
+	if v2 := fields["Version2"]; v2 != "" {
+		w.Version = v2
+	}
+	return w
+}
+
+// iwHexFlag reads a "0x01"-style boolean.
+func iwHexFlag(s string) bool {
+	s = strings.TrimPrefix(s, "0x")
+	return s != "" && strings.Trim(s, "0") != ""
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"wifiscanner/analysis"
+)
+
+// Interference range drawn by the congestion bars, dBm.
+const (
+	congestionFloor = -100
+	congestionCeil  = -30
+	congestionBar   = 30
+)
+
+func (a *App) buildChannels() {
+	a.channels = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	a.channels.
+		SetBorder(true).
+		SetBorderColor(tcell.GetColor(colorCyan)).
+		SetTitle(fmt.Sprintf(" [%s]◈[-] [%s]CHANNEL CONGESTION[-] [%s]◈[-] ",
+			colorHotPink, colorCyan, colorHotPink)).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderPadding(0, 0, 1, 1)
+}
+
+// updateChannels re-runs the congestion analysis over the latest scan and
+// the session history, within the regulatory domain once it is known.
+func (a *App) updateChannels() {
+	report := analysis.Analyze(a.networks, a.session, a.regDomain)
+
+	var b strings.Builder
+	if len(report.Recommendations) > 0 {
+		b.WriteString(fmt.Sprintf("[%s::b]RECOMMENDED[-::-]\n", colorMagenta))
+		for _, p := range report.Recommendations {
+			chans := make([]string, len(p.Channels))
+			for i, ch := range p.Channels {
+				chans[i] = fmt.Sprintf("%d", ch)
+			}
+			b.WriteString(fmt.Sprintf("  [%s]★[-] [%s]%-7s %3d MHz[-]  primary [%s]%-3d[-]  [%s]ch %s  %.1f dBm[-]\n",
+				colorHotPink, colorCyan, p.Band, p.Width, colorYellow, p.Primary,
+				colorMuted, strings.Join(chans, ","), p.Score))
+		}
+	}
+
+	band := ""
+	for _, cs := range report.Channels {
+		if cs.Band != band {
+			band = cs.Band
+			b.WriteString(fmt.Sprintf("\n[%s::b]%s[-::-]\n", colorMagenta, band))
+		}
+
+		filled := int((cs.Score - congestionFloor) * congestionBar / (congestionCeil - congestionFloor))
+		if filled < 0 {
+			filled = 0
+		}
+		if filled > congestionBar {
+			filled = congestionBar
+		}
+		_, color := signalBars(int(cs.Score))
+		if cs.CoChannel+cs.Adjacent == 0 {
+			color = colorGreen
+		}
+
+		b.WriteString(fmt.Sprintf("  [%s]%4d[-] [%s]%s[-][%s]%s[-] [%s]%6.1f dBm[-]  [%s]co %-2d adj %-2d[-]\n",
+			colorYellow, cs.Channel,
+			color, strings.Repeat("█", filled),
+			colorDim, strings.Repeat("░", congestionBar-filled),
+			color, cs.Score,
+			colorMuted, cs.CoChannel, cs.Adjacent))
+	}
+	if report.Networks == 0 {
+		b.WriteString(fmt.Sprintf("[%s]No networks to analyse[-]", colorMuted))
+	}
+
+	a.channels.SetText(b.String())
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"wifiscanner/scanner"
+)
+
+// hexDumpWidth is the number of bytes per raw element dump line; the detail
+// panel fits eight with their ASCII column.
+const hexDumpWidth = 8
+
+// detailElements renders every raw information element of net: ID, length
+// and name, then an offset/hex/ASCII dump of its body.
+func (a *App) detailElements(net scanner.Network) string {
+	var b strings.Builder
+	b.WriteString("\n")
+
+	elems := net.Elements()
+	if len(elems) == 0 {
+		msg := "No raw elements were captured for this network"
+		if !a.scanner.Backend.Capabilities().Has(scanner.CapRawIEs) {
+			msg = fmt.Sprintf("The %s backend does not provide raw elements", a.scanner.Backend.Name())
+		}
+		b.WriteString(fmt.Sprintf("  [%s]%s[-]\n", colorMuted, msg))
+	}
+
+	total := 0
+	for _, e := range elems {
+		total += 2 + len(e.Data)
+		b.WriteString(fmt.Sprintf("  [%s]%3d[-]  [%s]%-34s[-] [%s]len %d[-]\n",
+			colorYellow, e.ID, colorCyan, e.Name(), colorMuted, len(e.Data)))
+		for off := 0; off < len(e.Data); off += hexDumpWidth {
+			end := off + hexDumpWidth
+			if end > len(e.Data) {
+				end = len(e.Data)
+			}
+			b.WriteString(fmt.Sprintf("       [%s]%04x[-]  [%s]%-*s[-]  [%s]%s[-]\n",
+				colorDim, off, colorGreen, hexDumpWidth*3-1, hexBytes(e.Data[off:end]),
+				colorMuted, printable(e.Data[off:end])))
+		}
+	}
+	if len(elems) > 0 {
+		b.WriteString(fmt.Sprintf("\n  [%s]%d elements, %d bytes[-]\n", colorDim, len(elems), total))
+	}
+
+	b.WriteString(fmt.Sprintf("\n  [%s]Press X for the summary, Esc or Enter to close[-]", colorDim))
+	return b.String()
+}
+
+// hexBytes formats data as space-separated hex pairs.
+func hexBytes(data []byte) string {
+	parts := make([]string, len(data))
+	for i, c := range data {
+		parts[i] = fmt.Sprintf("%02x", c)
+	}
+	return strings.Join(parts, " ")
+}
+
+// printable renders data as ASCII, with dots for other bytes. Brackets are
+// replaced too, so the dump cannot form tview colour tags.
+func printable(data []byte) string {
+	out := make([]byte, len(data))
+	for i, c := range data {
+		if c >= ' ' && c < 0x7f && c != '[' && c != ']' {
+			out[i] = c
+		} else {
+			out[i] = '.'
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"wifiscanner/scanner"
+	"wifiscanner/scanner/channel"
+)
+
+// Signal range plotted on the spectrum y-axis, dBm.
+const (
+	spectrumFloor = -100
+	spectrumCeil  = -20
+)
+
+// spectrumBand is one x-axis of the spectrum view.
+type spectrumBand struct {
+	name     string
+	band     channel.Band
+	low      int // MHz at the left edge
+	high     int // MHz at the right edge
+	channels []int
+}
+
+var spectrumBands = []spectrumBand{
+	{"2.4 GHz", channel.Band2G, 2401, 2495, channel.Range(1, 14, 1)},
+	{"5 GHz", channel.Band5G, 5170, 5895, channel.Range(36, 177, 4)},
+	{"6 GHz", channel.Band6G, 5945, 7125, channel.Range(1, 233, 4)},
+}
+
+// spectrumView draws every visible network as a trapezoid over its occupied
+// channel span, one chart per band that has networks.
+type spectrumView struct {
+	*tview.Box
+	networks []scanner.Network
+}
+
+func newSpectrumView() *spectrumView {
+	v := &spectrumView{Box: tview.NewBox()}
+	v.SetBorder(true).
+		SetBorderColor(tcell.GetColor(colorCyan)).
+		SetTitle(fmt.Sprintf(" [%s]◈[-] [%s]SPECTRUM[-] [%s]◈[-] ",
+			colorHotPink, colorCyan, colorHotPink)).
+		SetTitleAlign(tview.AlignCenter)
+	return v
+}
+
+// SetNetworks replaces the networks to plot.
+func (v *spectrumView) SetNetworks(networks []scanner.Network) {
+	v.networks = networks
+}
+
+// Draw implements tview.Primitive.
+func (v *spectrumView) Draw(screen tcell.Screen) {
+	v.Box.DrawForSubclass(screen, v)
+	x, y, width, height := v.GetInnerRect()
+
+	perBand := make(map[channel.Band][]scanner.Network)
+	for _, n := range v.networks {
+		b := channel.BandOf(n.Frequency)
+		perBand[b] = append(perBand[b], n)
+	}
+
+	var bands []spectrumBand
+	for _, b := range spectrumBands {
+		if len(perBand[b.band]) > 0 {
+			bands = append(bands, b)
+		}
+	}
+	if len(bands) == 0 {
+		tview.Print(screen, "No networks to plot", x, y+height/2, width, tview.AlignCenter, tcell.GetColor(colorMuted))
+		return
+	}
+
+	chartHeight := height / len(bands)
+	for i, b := range bands {
+		v.drawBand(screen, b, perBand[b.band], x, y+i*chartHeight, width, chartHeight)
+	}
+}
+
+// drawBand draws one band chart: a title row, the plot, the frequency axis
+// and its channel labels.
+func (v *spectrumView) drawBand(screen tcell.Screen, b spectrumBand, networks []scanner.Network, x, y, width, height int) {
+	const axisWidth = 5 // room for "-100" plus a gap
+	plotX, plotW := x+axisWidth, width-axisWidth
+	plotTop, baseY := y+1, y+height-2 // baseline row, channel labels below it
+	rows := baseY - plotTop
+	if plotW < 10 || rows < 2 {
+		return
+	}
+
+	dim := tcell.StyleDefault.Foreground(tcell.GetColor(colorDim))
+	tview.Print(screen, fmt.Sprintf("[%s]%s[-]", colorMagenta, b.name), x, y, width, tview.AlignLeft, tcell.ColorDefault)
+
+	col := func(freq int) int {
+		return plotX + (freq-b.low)*(plotW-1)/(b.high-b.low)
+	}
+	freqAt := func(c int) int {
+		return b.low + (c-plotX)*(b.high-b.low)/(plotW-1)
+	}
+	level := func(signal int) int {
+		if signal < spectrumFloor {
+			signal = spectrumFloor
+		}
+		if signal > spectrumCeil {
+			signal = spectrumCeil
+		}
+		return (signal - spectrumFloor) * rows / (spectrumCeil - spectrumFloor)
+	}
+
+	// Y axis labels
+	for dbm := -90; dbm <= -30; dbm += 20 {
+		if row := baseY - level(dbm); row > plotTop {
+			tview.Print(screen, fmt.Sprintf("%d", dbm), x, row, axisWidth-1, tview.AlignRight, tcell.GetColor(colorDim))
+		}
+	}
+
+	// Baseline and channel labels, skipping labels that would collide
+	for c := plotX; c < plotX+plotW; c++ {
+		screen.SetContent(c, baseY, '─', nil, dim)
+	}
+	next := plotX
+	for _, ch := range b.channels {
+		c := col(channel.Frequency(b.band, ch))
+		label := fmt.Sprintf("%d", ch)
+		start := c - len(label)/2
+		if start < next || start+len(label) > plotX+plotW {
+			continue
+		}
+		screen.SetContent(c, baseY, '┴', nil, dim)
+		tview.Print(screen, label, start, baseY+1, len(label), tview.AlignLeft, tcell.GetColor(colorMuted))
+		next = start + len(label) + 1
+	}
+
+	// Weakest first so the strongest networks end up on top
+	sorted := append([]scanner.Network(nil), networks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Signal < sorted[j].Signal })
+
+	for _, n := range sorted {
+		_, color := signalBars(n.Signal)
+		style := tcell.StyleDefault.Foreground(tcell.GetColor(color))
+		peak := level(n.Signal)
+
+		for _, seg := range n.Span().Segments() {
+			// Edges slope over an eighth of the width, at least 2 MHz
+			ramp := (seg[1] - seg[0]) / 8
+			if ramp < 2 {
+				ramp = 2
+			}
+			left, right := col(seg[0]), col(seg[1])
+			if right <= left {
+				right = left + 1
+			}
+
+			prev := baseY
+			for c := left; c <= right && c < plotX+plotW; c++ {
+				f := freqAt(c)
+				shape := (f - seg[0]) * 100 / ramp
+				if s := (seg[1] - f) * 100 / ramp; s < shape {
+					shape = s
+				}
+				if shape > 100 {
+					shape = 100
+				}
+				if shape < 0 {
+					shape = 0
+				}
+				top := baseY - peak*shape/100
+				if c == right {
+					top = baseY
+				}
+
+				// Join steep slopes with vertical strokes
+				for r := top + 1; r < prev; r++ {
+					screen.SetContent(c, r, '│', nil, style)
+				}
+				for r := prev + 1; r < top; r++ {
+					screen.SetContent(c-1, r, '│', nil, style)
+				}
+				if top < baseY {
+					screen.SetContent(c, top, '─', nil, style)
+				}
+				prev = top
+			}
+		}
+
+		// SSID above the peak of the primary segment
+		if label := tview.Escape(n.SSID); baseY-peak-1 > plotTop {
+			center := col(n.Span().Center)
+			w := len([]rune(n.SSID))
+			if limit := col(n.Span().High()) - col(n.Span().Low()) + 4; w > limit {
+				w = limit
+			}
+			tview.Print(screen, label, center-w/2, baseY-peak-1, w, tview.AlignLeft, tcell.GetColor(color))
+		}
+	}
+}
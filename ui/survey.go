@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"wifiscanner/scanner/channel"
+)
+
+const utilisationBar = 40
+
+func (a *App) buildSurvey() {
+	a.survey = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	a.survey.
+		SetBorder(true).
+		SetBorderColor(tcell.GetColor(colorCyan)).
+		SetTitle(fmt.Sprintf(" [%s]◈[-] [%s]CHANNEL UTILISATION[-] [%s]◈[-] ",
+			colorHotPink, colorCyan, colorHotPink)).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderPadding(0, 0, 1, 1)
+}
+
+// updateSurvey redraws the utilisation chart from the last scan's channel
+// surveys.
+func (a *App) updateSurvey() {
+	surveys := a.scanner.Surveys()
+
+	var b strings.Builder
+	if len(surveys) == 0 {
+		b.WriteString(fmt.Sprintf("[%s]No survey data: the %s backend cannot survey channels, or the driver reported none[-]",
+			colorMuted, a.scanner.Backend.Name()))
+		a.survey.SetText(b.String())
+		return
+	}
+
+	multi := len(a.scanner.Interfaces) > 1
+	band := channel.BandUnknown
+	for _, c := range surveys {
+		if bd := channel.BandOf(c.Frequency); bd != band {
+			band = bd
+			b.WriteString(fmt.Sprintf("\n[%s::b]%s[-::-]\n", colorMagenta, band))
+		}
+
+		label := fmt.Sprintf("%4d", channel.Number(c.Frequency))
+		if multi {
+			label += fmt.Sprintf(" %-6s", c.Interface)
+		}
+
+		util := c.Utilisation()
+		bar := fmt.Sprintf("[%s]%s[-]", colorDim, strings.Repeat("·", utilisationBar))
+		pct := "   n/a"
+		if util >= 0 {
+			filled := int(util*utilisationBar/100 + 0.5)
+			bar = fmt.Sprintf("[%s]%s[-][%s]%s[-]",
+				utilisationColor(util), strings.Repeat("█", filled),
+				colorDim, strings.Repeat("░", utilisationBar-filled))
+			pct = fmt.Sprintf("%5.1f%%", util)
+		}
+
+		noise := "noise    n/a"
+		if c.Noise != 0 {
+			noise = fmt.Sprintf("noise %3d dBm", c.Noise)
+		}
+		inUse := ""
+		if c.InUse {
+			inUse = fmt.Sprintf("  [%s]◀ in use[-]", colorHotPink)
+		}
+
+		b.WriteString(fmt.Sprintf("  [%s]%s[-] [%s]%d MHz[-]  %s [%s]%s[-]  [%s]%s[-]%s\n",
+			colorYellow, label, colorMuted, c.Frequency, bar,
+			utilisationColor(util), pct, colorMuted, noise, inUse))
+	}
+
+	a.survey.SetText(b.String())
+}
+
+// utilisationColor grades channel busy time like signalBars grades signal.
+func utilisationColor(util float64) string {
+	switch {
+	case util < 0:
+		return colorDim
+	case util < 20:
+		return colorGreen
+	case util < 40:
+		return colorCyan
+	case util < 60:
+		return colorYellow
+	case util < 80:
+		return colorOrange
+	default:
+		return colorRed
+	}
+}
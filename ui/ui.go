@@ -1,15 +1,18 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
 	"wifiscanner/scanner"
+	"wifiscanner/scanner/channel"
 )
 
 // Cyberpunk neon color palette
@@ -23,6 +26,7 @@ const (
 	colorMuted   = "#888888"
 	colorYellow  = "#ffff00"
 	colorHotPink = "#ff1493"
+	colorPurple  = "#b388ff"
 	colorBg      = "#0a0a1a"
 
 	colorDarkMagenta = "#330033"
@@ -42,13 +46,34 @@ type App struct {
 	sortBy   string
 	scanning bool
 
+	// Auth mode filter ("" shows all) and the rows it leaves visible
+	authFilter scanner.AuthMode
+	visible    []scanner.Network
+
 	// Session state tracking
 	session *scanner.Session
 
+	// Local regulatory domain (fetched once, nil if unknown) and the
+	// per-BSSID problems found against it
+	regDomain *scanner.RegDomain
+	regOnce   sync.Once
+	regIssues map[string][]string
+
+	// Per-BSSID roaming features that disagree with other APs of the SSID
+	roamIssues map[string][]string
+
 	// Detail panel
 	detail      *tview.TextView
 	pages       *tview.Pages
 	detailShown bool
+	detailNet   scanner.Network // network the detail panel shows
+	detailRaw   bool            // showing the raw element page
+
+	// Pages shown instead of the table ("" while the table is shown)
+	spectrum *spectrumView
+	channels *tview.TextView
+	survey   *tview.TextView
+	view     string
 
 	// New network alerts
 	newBSSIDs map[string]time.Time
@@ -72,6 +97,9 @@ func (a *App) Run() error {
 	a.buildTable()
 	a.buildFooter()
 	a.buildDetail()
+	a.spectrum = newSpectrumView()
+	a.buildChannels()
+	a.buildSurvey()
 
 	// Main layout
 	layout := tview.NewFlex().
@@ -80,9 +108,21 @@ func (a *App) Run() error {
 		AddItem(a.table, 0, 1, true).
 		AddItem(a.footer, 1, 0, false)
 
+	// Alternate views share the header and footer
+	viewLayout := func(body tview.Primitive) *tview.Flex {
+		return tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(a.header, 5, 0, false).
+			AddItem(body, 0, 1, true).
+			AddItem(a.footer, 1, 0, false)
+	}
+
 	// Pages overlay for detail modal
 	a.pages = tview.NewPages().
 		AddPage("main", layout, true, true).
+		AddPage("spectrum", viewLayout(a.spectrum), true, false).
+		AddPage("channels", viewLayout(a.channels), true, false).
+		AddPage("survey", viewLayout(a.survey), true, false).
 		AddPage("detail", a.buildDetailModal(), true, false)
 
 	// Global keybindings
@@ -100,11 +140,17 @@ func (a *App) Run() error {
 				a.hideDetail()
 				return nil
 			}
+			if a.view != "" {
+				return nil
+			}
 			a.showDetail()
 			return nil
 		case tcell.KeyRune:
 			if a.detailShown {
-				return nil // Ignore rune keys while detail is open
+				if r := event.Rune(); r == 'x' || r == 'X' {
+					a.toggleDetailRaw()
+				}
+				return nil // Ignore other rune keys while detail is open
 			}
 			switch event.Rune() {
 			case 'q', 'Q':
@@ -116,6 +162,18 @@ func (a *App) Run() error {
 			case 's', 'S':
 				a.cycleSortOrder()
 				return nil
+			case 'f', 'F':
+				a.cycleFilter()
+				return nil
+			case 'v', 'V':
+				a.toggleView("spectrum")
+				return nil
+			case 'c', 'C':
+				a.toggleView("channels")
+				return nil
+			case 'u', 'U':
+				a.toggleView("survey")
+				return nil
 			}
 		}
 		return event
@@ -151,12 +209,15 @@ func (a *App) buildHeader() {
 }
 
 func (a *App) updateHeader() {
-	mode := fmt.Sprintf("[%s]◉ LIVE[-]", colorGreen)
-	if a.scanner.Demo {
+	mode := fmt.Sprintf("[%s]◉ LIVE[-] [%s]%s[-]", colorGreen, colorDim, a.scanner.Backend.Name())
+	switch a.scanner.Backend.Name() {
+	case "demo":
 		mode = fmt.Sprintf("[%s]◉ DEMO[-]", colorOrange)
+	case "replay":
+		mode = fmt.Sprintf("[%s]◉ REPLAY[-]", colorOrange)
 	}
 
-	iface := a.scanner.Interface
+	iface := strings.Join(a.scanner.Interfaces, ",")
 	if iface == "" {
 		iface = "..."
 	}
@@ -179,12 +240,21 @@ func (a *App) updateHeader() {
 		"[%s]WiFi Spectrum Analyzer[-]    %s    [%s]Status:[-] [%s]%s[-]",
 		colorMuted, mode, colorDim, statusColor, status,
 	)
+	if a.regDomain != nil {
+		line1 += fmt.Sprintf("    [%s]Reg:[-] [%s]%s[-]", colorDim, colorCyan, a.regDomain.Country)
+	}
+	filter := "ALL"
+	if a.authFilter != "" {
+		filter = string(a.authFilter)
+	}
+
 	line2 := fmt.Sprintf(
-		"[%s]Interface:[-] [%s]%s[-]  [%s]│[-]  [%s]Networks:[-] [%s]%s[-]  [%s]│[-]  [%s]Last Scan:[-] [%s]%s[-]  [%s]│[-]  [%s]Sort:[-] [%s]%s[-]",
+		"[%s]Interface:[-] [%s]%s[-]  [%s]│[-]  [%s]Networks:[-] [%s]%s[-]  [%s]│[-]  [%s]Last Scan:[-] [%s]%s[-]  [%s]│[-]  [%s]Sort:[-] [%s]%s[-]  [%s]│[-]  [%s]Filter:[-] [%s]%s[-]",
 		colorDim, colorCyan, iface, colorDim,
 		colorDim, colorCyan, netCount, colorDim,
 		colorDim, colorCyan, scanTime, colorDim,
-		colorDim, colorGreen, strings.ToUpper(a.sortBy),
+		colorDim, colorGreen, strings.ToUpper(a.sortBy), colorDim,
+		colorDim, colorGreen, filter,
 	)
 
 	a.header.SetText(line1 + "\n" + line2)
@@ -222,10 +292,13 @@ func (a *App) setTableHeaders() {
 		{"SSID", 24, 1, tview.AlignLeft},
 		{"BSSID", 17, 0, tview.AlignLeft},
 		{"VENDOR", 16, 0, tview.AlignLeft},
-		{"CH", 4, 0, tview.AlignRight},
+		{"CH", 8, 0, tview.AlignRight},
 		{"FREQ", 6, 0, tview.AlignRight},
 		{"BAND", 5, 0, tview.AlignCenter},
-		{"SECURITY", 10, 0, tview.AlignLeft},
+		{"GEN", 4, 0, tview.AlignCenter},
+		{"LOAD", 8, 0, tview.AlignRight},
+		{"WPS", 4, 0, tview.AlignCenter},
+		{"SECURITY", 28, 0, tview.AlignLeft},
 	}
 
 	for i, h := range headers {
@@ -246,9 +319,22 @@ func (a *App) updateTable() {
 		a.table.RemoveRow(r)
 	}
 
+	a.visible = a.visible[:0]
+	for _, net := range scanner.GroupOWETransition(a.networks) {
+		if a.authFilter == "" || net.SecurityInfo.AuthMode() == a.authFilter {
+			a.visible = append(a.visible, net)
+		}
+	}
+
+	a.regIssues = scanner.CheckRegulatory(a.networks, a.regDomain)
+	a.roamIssues = scanner.CheckRoaming(a.networks)
+	a.spectrum.SetNetworks(a.visible)
+	a.updateChannels()
+	a.updateSurvey()
+
 	now := time.Now()
 
-	for i, net := range a.networks {
+	for i, net := range a.visible {
 		row := i + 1
 
 		// Check if this network is "new"
@@ -289,14 +375,20 @@ func (a *App) updateTable() {
 			SetTextColor(tcell.GetColor(colorCyan)).
 			SetBackgroundColor(rowBg))
 
-		// Col 3: SSID (with NEW badge if applicable)
+		// Col 3: SSID (with NEW and regulatory badges if applicable)
 		ssidText := net.SSID
 		ssidColor := colorCyan
 		if net.SSID == "<hidden>" {
 			ssidColor = colorDim
 		}
+		if len(a.regIssues[net.BSSID]) > 0 {
+			ssidText = fmt.Sprintf("[%s]⚑[-] %s", colorRed, ssidText)
+		}
+		if len(a.roamIssues[net.BSSID]) > 0 {
+			ssidText = fmt.Sprintf("[%s]⇄[-] %s", colorOrange, ssidText)
+		}
 		if isNew {
-			ssidText = fmt.Sprintf("[%s]NEW[-] %s", colorHotPink, net.SSID)
+			ssidText = fmt.Sprintf("[%s]NEW[-] %s", colorHotPink, ssidText)
 			a.table.SetCell(row, 3, tview.NewTableCell(ssidText).
 				SetTextColor(tcell.GetColor(ssidColor)).
 				SetExpansion(1).
@@ -323,8 +415,12 @@ func (a *App) updateTable() {
 			SetTextColor(tcell.GetColor(vendorColor)).
 			SetBackgroundColor(rowBg))
 
-		// Col 6: Channel
-		a.table.SetCell(row, 6, tview.NewTableCell(fmt.Sprintf("%d", net.Channel)).
+		// Col 6: Channel, with a badge for radar (DFS) channels
+		chText := fmt.Sprintf("%d", net.Channel)
+		if radar := net.Span().Radar(); radar != channel.RadarNone {
+			chText = fmt.Sprintf("[%s]%s[-] %d", radarColor(radar), radar, net.Channel)
+		}
+		a.table.SetCell(row, 6, tview.NewTableCell(chText).
 			SetTextColor(tcell.GetColor(colorYellow)).
 			SetAlign(tview.AlignRight).
 			SetBackgroundColor(rowBg))
@@ -342,9 +438,35 @@ func (a *App) updateTable() {
 			SetAlign(tview.AlignCenter).
 			SetBackgroundColor(rowBg))
 
-		// Col 9: Security
-		a.table.SetCell(row, 9, tview.NewTableCell(net.Security).
+		// Col 9: Wi-Fi generation
+		gen, genColor := genInfo(net)
+		a.table.SetCell(row, 9, tview.NewTableCell(gen).
+			SetTextColor(tcell.GetColor(genColor)).
+			SetAlign(tview.AlignCenter).
+			SetBackgroundColor(rowBg))
+
+		// Col 10: BSS Load (stations and AP-reported utilisation)
+		load, loadColor := "-", colorDim
+		if l := net.BSSLoad; l != nil {
+			util := l.UtilisationPercent()
+			load, loadColor = fmt.Sprintf("%d %3.0f%%", l.StationCount, util), utilisationColor(util)
+		}
+		a.table.SetCell(row, 10, tview.NewTableCell(load).
+			SetTextColor(tcell.GetColor(loadColor)).
+			SetAlign(tview.AlignRight).
+			SetBackgroundColor(rowBg))
+
+		// Col 11: WPS
+		wps, wpsColor := wpsInfo(net)
+		a.table.SetCell(row, 11, tview.NewTableCell(wps).
+			SetTextColor(tcell.GetColor(wpsColor)).
+			SetAlign(tview.AlignCenter).
+			SetBackgroundColor(rowBg))
+
+		// Col 12: Security
+		a.table.SetCell(row, 12, tview.NewTableCell(securityLabel(net)).
 			SetTextColor(tcell.GetColor(securityColor(net.Security))).
+			SetMaxWidth(28).
 			SetBackgroundColor(rowBg))
 	}
 }
@@ -379,13 +501,44 @@ func (a *App) buildDetailModal() *tview.Flex {
 
 func (a *App) showDetail() {
 	row, _ := a.table.GetSelection()
-	if row < 1 || row > len(a.networks) {
+	if row < 1 || row > len(a.visible) {
 		return
 	}
-	net := a.networks[row-1]
+	a.detailNet = a.visible[row-1]
+	a.detailRaw = false
+	a.renderDetail()
+
+	a.detailShown = true
+	a.pages.ShowPage("detail")
+	a.app.SetFocus(a.detail)
+}
+
+// renderDetail fills the detail panel with the summary or the raw element
+// page of the selected network.
+func (a *App) renderDetail() {
+	title, text := "NETWORK DETAIL", a.detailSummary(a.detailNet)
+	if a.detailRaw {
+		title, text = "RAW ELEMENTS", a.detailElements(a.detailNet)
+	}
+	a.detail.SetTitle(fmt.Sprintf(" [%s]◈[-] [%s]%s[-] [%s]◈[-] ",
+		colorHotPink, colorCyan, title, colorHotPink))
+	a.detail.SetText(text)
+	a.detail.ScrollToBeginning()
+}
+
+// toggleDetailRaw switches the detail panel between its two pages.
+func (a *App) toggleDetailRaw() {
+	a.detailRaw = !a.detailRaw
+	a.renderDetail()
+}
 
+// detailSummary renders the decoded fields of net.
+func (a *App) detailSummary(net scanner.Network) string {
 	vendor := scanner.LookupVendor(net.BSSID)
-	band, _ := bandInfo(net.Frequency)
+	band := channel.BandOf(net.Frequency).String()
+	if oc := net.Span().OperatingClass(); oc != 0 {
+		band += fmt.Sprintf("  (operating class %d)", oc)
+	}
 
 	// Build detail content
 	var b strings.Builder
@@ -397,6 +550,9 @@ func (a *App) showDetail() {
 	writeLine("SSID", net.SSID, colorCyan)
 	writeLine("BSSID", net.BSSID, colorMuted)
 	writeLine("VENDOR", vendor, colorGreen)
+	if net.APName != "" {
+		writeLine("AP NAME", tview.Escape(net.APName), colorCyan)
+	}
 
 	// Signal with min/max from session
 	sigStr := fmt.Sprintf("%d dBm", net.Signal)
@@ -405,6 +561,26 @@ func (a *App) showDetail() {
 	}
 	_, barColor := signalBars(net.Signal)
 	writeLine("SIGNAL", sigStr, barColor)
+	if snr, ok := net.SNR(); ok {
+		writeLine("NOISE", fmt.Sprintf("%d dBm", net.Noise), colorMuted)
+		writeLine("SNR", fmt.Sprintf("%d dB", snr), snrColor(snr))
+	}
+
+	// Per-adapter readings when scanning several interfaces
+	if len(a.scanner.Interfaces) > 1 {
+		heard := make(map[string]int)
+		for _, r := range net.Readings {
+			heard[r.Interface] = r.Signal
+		}
+		for _, iface := range a.scanner.Interfaces {
+			if sig, ok := heard[iface]; ok {
+				_, color := signalBars(sig)
+				writeLine("  "+iface, fmt.Sprintf("%d dBm", sig), color)
+			} else {
+				writeLine("  "+iface, "not heard", colorDim)
+			}
+		}
+	}
 
 	// Sparkline
 	if state := a.session.Get(net.BSSID); state != nil {
@@ -414,10 +590,197 @@ func (a *App) showDetail() {
 		}
 	}
 
-	writeLine("CHANNEL", fmt.Sprintf("%d", net.Channel), colorYellow)
+	chDetail := fmt.Sprintf("%d", net.Channel)
+	if radar := net.Span().Radar(); radar != channel.RadarNone {
+		chDetail += fmt.Sprintf("  [%s]%s[-]", radarColor(radar), radar)
+		if radar == channel.RadarWeather {
+			chDetail += "  (weather radar band)"
+		}
+	}
+	writeLine("CHANNEL", chDetail, colorYellow)
+	if state := a.session.Get(net.BSSID); state != nil && state.ChannelMoves > 0 {
+		writeLine("  MOVES", fmt.Sprintf("%d this session", state.ChannelMoves), colorOrange)
+	}
 	writeLine("FREQUENCY", fmt.Sprintf("%d MHz", net.Frequency), colorMuted)
 	writeLine("BAND", band, colorCyan)
-	writeLine("SECURITY", net.Security, securityColor(net.Security))
+	if phy := net.PHY; len(phy.Standards()) > 0 {
+		_, genColor := genInfo(net)
+		writeLine("GENERATION", fmt.Sprintf("%s  (%s)", net.Generation(), strings.Join(phy.Standards(), " ")), genColor)
+		span := net.Span()
+		var chans []string
+		for _, ch := range span.Channels() {
+			chans = append(chans, fmt.Sprintf("%d", ch))
+		}
+		writeLine("  SPAN", fmt.Sprintf("%s  (ch %s)", span, strings.Join(chans, ",")), colorMuted)
+		writeLine("  STREAMS", fmt.Sprintf("%d", phy.SpatialStreams), colorMuted)
+		writeLine("  GUARD INT", strings.Join(phy.GuardIntervals(), " "), colorMuted)
+		var mcs []string
+		if phy.HT {
+			mcs = append(mcs, fmt.Sprintf("HT 0-%d", phy.HTMCS))
+		}
+		if phy.VHT {
+			mcs = append(mcs, fmt.Sprintf("VHT 0-%d", phy.VHTMCS))
+		}
+		if phy.HE {
+			mcs = append(mcs, fmt.Sprintf("HE 0-%d", phy.HEMCS))
+		}
+		if phy.EHT {
+			mcs = append(mcs, fmt.Sprintf("EHT 0-%d", phy.EHTMCS))
+		}
+		writeLine("  MCS", strings.Join(mcs, "  "), colorMuted)
+	}
+	if l := net.BSSLoad; l != nil {
+		util := l.UtilisationPercent()
+		load := fmt.Sprintf("%d stations  %.0f%% utilisation", l.StationCount, util)
+		if state := a.session.Get(net.BSSID); state != nil {
+			load += fmt.Sprintf("  (peak %d)", state.PeakStations)
+		}
+		writeLine("BSS LOAD", load, utilisationColor(util))
+		writeLine("  ADMISSION", fmt.Sprintf("%d × 32 µs/s", l.AdmissionCapacity), colorMuted)
+		if state := a.session.Get(net.BSSID); state != nil {
+			if spark := state.LoadSparkline(); spark != "" {
+				writeLine("  LOAD HIST", spark, colorCyan)
+			}
+		}
+	}
+	writeLine("MAX PHY RATE", fmt.Sprintf("%.0f Mbps", net.MaxPHYRate()), colorCyan)
+	writeLine("EST THROUGHPUT", fmt.Sprintf("%.0f Mbps  (2x2 client at %d dBm)", net.EstimatedThroughput(), net.Signal), barColor)
+	if c := net.Country; c != nil {
+		writeLine("COUNTRY", fmt.Sprintf("%s  (%s)", tview.Escape(c.Code), tview.Escape(c.Environment)), colorCyan)
+		for _, sb := range c.Subbands {
+			last := sb.FirstChannel + sb.NumChannels - 1
+			if sb.FirstChannel > 14 {
+				last = sb.FirstChannel + (sb.NumChannels-1)*4
+			}
+			writeLine("", fmt.Sprintf("ch %d-%d @ %d dBm", sb.FirstChannel, last, sb.MaxPower), colorMuted)
+		}
+	}
+	if a.regDomain != nil {
+		if rule, ok := a.regDomain.Rule(net.Frequency); ok {
+			reg := fmt.Sprintf("permitted in %s, max %d dBm EIRP", a.regDomain.Country, rule.MaxEIRP)
+			if len(rule.Flags) > 0 {
+				reg += "  " + strings.Join(rule.Flags, " ")
+			}
+			writeLine("REGULATORY", reg, colorMuted)
+		}
+	}
+	for _, issue := range a.regIssues[net.BSSID] {
+		writeLine("⚑ REGULATORY", issue, colorRed)
+	}
+	writeLine("SECURITY", securityLabel(net), securityColor(net.Security))
+	if t := net.OWETransition; t != nil {
+		pair := fmt.Sprintf("%s  %s", t.BSSID, t.SSID)
+		if t.BSSID == "" {
+			pair = "partner not reported"
+		} else if t.Partner != nil {
+			pair += fmt.Sprintf("  (%d dBm)", t.Partner.Signal)
+		} else {
+			pair += "  (not seen)"
+		}
+		writeLine("  OWE PAIR", pair, colorMuted)
+	}
+	if si := net.SecurityInfo; si.RSN || si.WPA {
+		if si.GroupCipher != "" {
+			writeLine("  GROUP", si.GroupCipher, colorMuted)
+		}
+		if len(si.PairwiseCiphers) > 0 {
+			writeLine("  PAIRWISE", strings.Join(si.PairwiseCiphers, " "), colorMuted)
+		}
+		if len(si.AKMs) > 0 {
+			writeLine("  AKM", strings.Join(si.AKMs, " "), colorMuted)
+		}
+		pmf := "disabled"
+		switch {
+		case si.PMFRequired:
+			pmf = "required"
+		case si.PMFCapable:
+			pmf = "capable"
+		}
+		writeLine("  PMF", pmf, colorMuted)
+		if si.HasCapabilities {
+			writeLine("  RSN CAPS", fmt.Sprintf("0x%04x", si.RSNCapabilities), colorMuted)
+		}
+	}
+
+	roam := net.Roaming
+	var badges []string
+	for _, f := range []struct {
+		badge string
+		on    bool
+	}{{"k", roam.RRM}, {"v", roam.BSSTransition}, {"r", roam.FastTransition()}} {
+		color := colorDim
+		if f.on {
+			color = colorGreen
+		}
+		badges = append(badges, fmt.Sprintf("[%s::b]%s[-::-]", color, f.badge))
+	}
+	writeLine("ROAMING", strings.Join(badges, " "), colorMuted)
+	if roam.MobilityDomain {
+		md := fmt.Sprintf("0x%04x", roam.MDID)
+		if roam.FTOverDS {
+			md += "  FT over DS"
+		}
+		writeLine("  MOBILITY DOM", md, colorMuted)
+	}
+	if roam.RRM && roam.NeighborReport {
+		writeLine("  802.11K", "neighbor report", colorMuted)
+	}
+	for _, issue := range a.roamIssues[net.BSSID] {
+		writeLine("⇄ ROAMING", issue, colorOrange)
+	}
+
+	if w := net.WPS; w != nil {
+		wpsLabel, wpsColor := wpsInfo(net)
+		state := "unconfigured"
+		if w.Configured {
+			state = "configured"
+		}
+		if w.Locked {
+			state += ", setup locked"
+		}
+		if w.SelectedRegistrar {
+			state += ", registrar active"
+		}
+		writeLine("WPS", fmt.Sprintf("%s  v%s  %s", wpsLabel, w.Version, state), wpsColor)
+		if w.DeviceName != "" {
+			writeLine("  DEVICE", tview.Escape(w.DeviceName), colorCyan)
+		}
+		if id := w.Identity(); id != "" {
+			writeLine("  MODEL", tview.Escape(id), colorGreen)
+		}
+		if w.SerialNumber != "" {
+			writeLine("  SERIAL", tview.Escape(w.SerialNumber), colorMuted)
+		}
+		if w.UUID != "" {
+			writeLine("  UUID", tview.Escape(w.UUID), colorMuted)
+		}
+	}
+
+	// Decoded capability and vendor elements, grouped by element. Flags
+	// share one line; named values get a line each.
+	var sources []string
+	flags := make(map[string][]string)
+	values := make(map[string][]scanner.Attribute)
+	for _, attr := range net.Attributes {
+		if _, ok := flags[attr.Source]; !ok {
+			sources = append(sources, attr.Source)
+			flags[attr.Source] = nil
+		}
+		if attr.Value == "" {
+			flags[attr.Source] = append(flags[attr.Source], attr.Name)
+		} else {
+			values[attr.Source] = append(values[attr.Source], attr)
+		}
+	}
+	if len(sources) > 0 {
+		b.WriteString(fmt.Sprintf("  [%s]ELEMENTS[-]\n", colorDim))
+	}
+	for _, src := range sources {
+		b.WriteString(fmt.Sprintf("    [%s]%s[-]  [%s]%s[-]\n", colorMagenta, src, colorMuted, strings.Join(flags[src], ", ")))
+		for _, attr := range values[src] {
+			b.WriteString(fmt.Sprintf("      [%s]%-12s[-]  [%s]%s[-]\n", colorDim, attr.Name, colorMuted, tview.Escape(attr.Value)))
+		}
+	}
 
 	// First/Last seen from session
 	if state := a.session.Get(net.BSSID); state != nil {
@@ -425,12 +788,34 @@ func (a *App) showDetail() {
 		writeLine("LAST SEEN", state.LastSeen.Format("15:04:05"), colorMuted)
 	}
 
-	b.WriteString(fmt.Sprintf("\n  [%s]Press Esc or Enter to close[-]", colorDim))
+	// The TSF was read when the network was last seen; by the latest scan
+	// the AP has been up that much longer. Replayed scans carry their
+	// capture time, so the clock is not used.
+	if net.TSF != 0 {
+		uptime := net.Uptime()
+		if latest := a.latestScan(); !net.LastSeen.IsZero() && latest.After(net.LastSeen) {
+			uptime += latest.Sub(net.LastSeen)
+		}
+		writeLine("UPTIME", scanner.FormatUptime(uptime)+"  (estimated from TSF)", colorGreen)
+	}
+	if state := a.session.Get(net.BSSID); state != nil && state.Reboots > 0 {
+		writeLine("  REBOOTS", fmt.Sprintf("%d this session, last at %s", state.Reboots, state.LastReboot.Format("15:04:05")), colorRed)
+	}
+
+	b.WriteString(fmt.Sprintf("\n  [%s]Press X for raw elements, Esc or Enter to close[-]", colorDim))
+	return b.String()
+}
 
-	a.detail.SetText(b.String())
-	a.detailShown = true
-	a.pages.ShowPage("detail")
-	a.app.SetFocus(a.detail)
+// latestScan returns when the newest result was seen: the time of the last
+// scan, or of the capture being replayed.
+func (a *App) latestScan() time.Time {
+	var latest time.Time
+	for _, n := range a.networks {
+		if n.LastSeen.After(latest) {
+			latest = n.LastSeen
+		}
+	}
+	return latest
 }
 
 func (a *App) hideDetail() {
@@ -439,6 +824,30 @@ func (a *App) hideDetail() {
 	a.app.SetFocus(a.table)
 }
 
+// ── Views ───────────────────────────────────────────────────────────────────
+
+// toggleView switches between the table and the named page ("spectrum",
+// "channels" or "survey"); toggling the page already shown returns to the
+// table.
+func (a *App) toggleView(name string) {
+	if a.view == name {
+		a.view = ""
+		a.pages.SwitchToPage("main")
+		a.app.SetFocus(a.table)
+		return
+	}
+	a.view = name
+	a.pages.SwitchToPage(name)
+	switch name {
+	case "spectrum":
+		a.app.SetFocus(a.spectrum)
+	case "channels":
+		a.app.SetFocus(a.channels)
+	case "survey":
+		a.app.SetFocus(a.survey)
+	}
+}
+
 // ── Footer ──────────────────────────────────────────────────────────────────
 
 func (a *App) buildFooter() {
@@ -451,7 +860,11 @@ func (a *App) buildFooter() {
 
 func (a *App) setDefaultFooter() {
 	a.footer.SetText(fmt.Sprintf(
-		" [%s][Q][-][%s]uit  [%s][R][-][%s]escan  [%s][S][-][%s]ort  [%s][Enter][-][%s] Detail  [%s][↑↓][-][%s] Navigate[-]  [%s]│[-]  [%s]Auto-refresh: 10s[-]",
+		" [%s][Q][-][%s]uit  [%s][R][-][%s]escan  [%s][S][-][%s]ort  [%s][F][-][%s]ilter  [%s][V][-][%s]iew  [%s][C][-][%s]hannels  [%s][U][-][%s]tilisation  [%s][Enter][-][%s] Detail  [%s][↑↓][-][%s] Navigate[-]  [%s]│[-]  [%s]Auto-refresh: 10s[-]",
+		colorCyan, colorMuted,
+		colorCyan, colorMuted,
+		colorCyan, colorMuted,
+		colorCyan, colorMuted,
 		colorCyan, colorMuted,
 		colorCyan, colorMuted,
 		colorCyan, colorMuted,
@@ -480,9 +893,33 @@ func (a *App) showNewNetworkAlert(count int) {
 	}()
 }
 
+// showSessionAlerts puts the latest session alert in the footer for a few
+// seconds, noting how many more arrived with it.
+func (a *App) showSessionAlerts(alerts []scanner.Alert) {
+	latest := alerts[len(alerts)-1]
+	color := colorOrange
+	if latest.Kind == scanner.AlertRadar || latest.Kind == scanner.AlertReboot {
+		color = colorRed
+	}
+	text := fmt.Sprintf(" [%s]⚠ %s[-]", color, tview.Escape(latest.Message))
+	if len(alerts) > 1 {
+		text += fmt.Sprintf("  [%s](+%d more)[-]", colorMuted, len(alerts)-1)
+	}
+	a.footer.SetText(text)
+
+	go func() {
+		time.Sleep(8 * time.Second)
+		a.app.QueueUpdateDraw(func() {
+			a.setDefaultFooter()
+		})
+	}()
+}
+
 // ── Scanning ────────────────────────────────────────────────────────────────
 
-func (a *App) doScan() {
+// doScan runs one scan and applies the results. The scan error is returned
+// so paced backends can stop the refresh loop when they run dry.
+func (a *App) doScan() error {
 	a.app.QueueUpdateDraw(func() {
 		a.scanning = true
 		a.updateHeader()
@@ -490,9 +927,25 @@ func (a *App) doScan() {
 
 	networks, err := a.scanner.Scan()
 
+	// The regulatory domain rarely changes; one lookup per run is enough
+	var reg *scanner.RegDomain
+	fetchReg := false
+	a.regOnce.Do(func() {
+		reg, _ = a.scanner.Regulatory()
+		fetchReg = true
+	})
+
 	a.app.QueueUpdateDraw(func() {
 		a.scanning = false
-		if err != nil {
+		if fetchReg {
+			a.regDomain = reg
+		}
+		if errors.Is(err, scanner.ErrReplayDone) {
+			a.footer.SetText(fmt.Sprintf(" [%s]■ Replay finished[-]  [%s][Q][-][%s]uit[-]", colorOrange, colorCyan, colorMuted))
+			a.updateHeader()
+			return
+		}
+		if err != nil && len(networks) == 0 {
 			a.footer.SetText(fmt.Sprintf(" [%s]✗ Scan error: %v[-]", colorRed, err))
 			a.updateHeader()
 			return
@@ -518,14 +971,37 @@ func (a *App) doScan() {
 		a.updateHeader()
 		a.updateTable()
 
+		// Some interfaces failed but others returned results
+		if err != nil {
+			a.footer.SetText(fmt.Sprintf(" [%s]⚠ Partial scan: %v[-]", colorOrange, err))
+			return
+		}
+
+		// Session alerts take precedence over the new-network notice
+		if alerts := a.session.Alerts(); len(alerts) > 0 {
+			a.showSessionAlerts(alerts)
+			return
+		}
+
 		// Show alert if new networks found (skip first scan)
 		if len(newBSSIDs) > 0 && a.session.Count() > len(newBSSIDs) {
 			a.showNewNetworkAlert(len(newBSSIDs))
 		}
 	})
+
+	return err
 }
 
 func (a *App) autoRefresh() {
+	// Paced backends (replay) block until their next result is due
+	if a.scanner.Backend.Capabilities().Has(scanner.CapPaced) {
+		for {
+			if err := a.doScan(); errors.Is(err, scanner.ErrReplayDone) {
+				return
+			}
+		}
+	}
+
 	ticker := time.NewTicker(refreshInterval)
 	defer ticker.Stop()
 
@@ -553,6 +1029,11 @@ func (a *App) cycleSortOrder() {
 		sort.Slice(a.networks, func(i, j int) bool {
 			return a.networks[i].Security < a.networks[j].Security
 		})
+	case "security":
+		a.sortBy = "rate"
+		sort.Slice(a.networks, func(i, j int) bool {
+			return a.networks[i].EstimatedThroughput() > a.networks[j].EstimatedThroughput()
+		})
 	default:
 		a.sortBy = "signal"
 		sort.Slice(a.networks, func(i, j int) bool {
@@ -564,6 +1045,33 @@ func (a *App) cycleSortOrder() {
 	a.updateTable()
 }
 
+// ── Filtering ───────────────────────────────────────────────────────────────
+
+// cycleFilter steps through the auth modes present in the current results,
+// wrapping back to showing everything.
+func (a *App) cycleFilter() {
+	present := make(map[scanner.AuthMode]bool)
+	for _, net := range a.networks {
+		present[net.SecurityInfo.AuthMode()] = true
+	}
+
+	next := scanner.AuthMode("")
+	passed := a.authFilter == ""
+	for _, mode := range scanner.AuthModes {
+		if passed && present[mode] {
+			next = mode
+			break
+		}
+		if mode == a.authFilter {
+			passed = true
+		}
+	}
+	a.authFilter = next
+
+	a.updateHeader()
+	a.updateTable()
+}
+
 // ── Helpers ─────────────────────────────────────────────────────────────────
 
 // signalBars maps dBm to a bar count (0–10) and a color.
@@ -594,15 +1102,87 @@ func signalBars(signal int) (int, string) {
 	}
 }
 
+// bandColors colours the BAND column.
+var bandColors = map[channel.Band]string{
+	channel.BandS1G: colorOrange,
+	channel.Band2G:  colorGreen,
+	channel.Band49G: colorYellow,
+	channel.Band5G:  colorCyan,
+	channel.Band6G:  colorMagenta,
+	channel.Band60G: colorPurple,
+}
+
 func bandInfo(freq int) (string, string) {
+	b := channel.BandOf(freq)
+	if color, ok := bandColors[b]; ok {
+		return b.Short(), color
+	}
+	return b.Short(), colorDim
+}
+
+// wpsInfo returns the WPS column label: unconfigured APs can be taken over
+// outright, configured ones still accept PIN attempts until locked.
+func wpsInfo(net scanner.Network) (string, string) {
+	switch w := net.WPS; {
+	case w == nil:
+		return "-", colorDim
+	case !w.Configured:
+		return "UNCF", colorRed
+	case w.Locked:
+		return "LOCK", colorMuted
+	default:
+		return "ON", colorOrange
+	}
+}
+
+// radarColor is the badge colour for a DFS requirement.
+func radarColor(r channel.Radar) string {
+	if r == channel.RadarWeather {
+		return colorRed
+	}
+	return colorOrange
+}
+
+// snrColor grades a signal-to-noise ratio: 40 dB and up is excellent,
+// under 15 dB is barely usable.
+func snrColor(snr int) string {
 	switch {
-	case freq >= 5955:
-		return "6G", colorMagenta
-	case freq >= 5000:
-		return "5G", colorCyan
+	case snr >= 40:
+		return colorGreen
+	case snr >= 25:
+		return colorCyan
+	case snr >= 15:
+		return colorYellow
 	default:
-		return "2.4G", colorGreen
+		return colorRed
+	}
+}
+
+// genInfo returns the short Wi-Fi generation ("6E") and its colour.
+func genInfo(net scanner.Network) (string, string) {
+	gen := strings.TrimPrefix(net.Generation(), "Wi-Fi ")
+	switch gen {
+	case "7", "6E":
+		return gen, colorMagenta
+	case "6":
+		return gen, colorCyan
+	case "5":
+		return gen, colorGreen
+	case "4":
+		return gen, colorYellow
+	default:
+		return "-", colorDim
+	}
+}
+
+// securityLabel is the network's security summary, naming merged OWE
+// transition pairs as such.
+func securityLabel(net scanner.Network) string {
+	label := net.SecurityInfo.Label()
+	if net.Security == "OWE-TM" {
+		label = "OWE-Transition" + strings.TrimPrefix(label, "OWE")
 	}
+	return label
 }
 
 func securityColor(sec string) string {
@@ -615,6 +1195,10 @@ func securityColor(sec string) string {
 		return colorOrange
 	case "WPA3":
 		return colorCyan
+	case "WPA-ENT", "WPA2-ENT", "WPA3-ENT", "WPA3-ENT-192":
+		return colorPurple
+	case "OWE", "OWE-TM":
+		return colorYellow
 	default:
 		return colorGreen
 	}